@@ -1,22 +1,41 @@
 package enclave
 
 import (
+	"errors"
 	"log/slog"
+	"os"
+	"slices"
 	"testing"
 
-	"github.com/joho/godotenv"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/fxtest"
 
+	"github.com/bosonicalio/enclave/config"
+
 	"github.com/bosonicalio/enclave/internal/applicationfx"
+	"github.com/bosonicalio/enclave/internal/authnfx"
+	"github.com/bosonicalio/enclave/internal/authzfx"
 	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/healthfx"
+	"github.com/bosonicalio/enclave/internal/observabilityfx/errorreportingfx"
 	"github.com/bosonicalio/enclave/internal/observabilityfx/loggingfx"
+	"github.com/bosonicalio/enclave/internal/observabilityfx/metricsfx"
+	"github.com/bosonicalio/enclave/internal/observabilityfx/profilingfx"
+	"github.com/bosonicalio/enclave/internal/observabilityfx/tracingfx"
+	"github.com/bosonicalio/enclave/internal/osenv"
 	"github.com/bosonicalio/enclave/internal/persistencefx"
 	"github.com/bosonicalio/enclave/internal/persistencefx/sqlfx"
+	"github.com/bosonicalio/enclave/internal/ratelimitfx"
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
 	"github.com/bosonicalio/enclave/internal/transportfx/httpfx"
 	"github.com/bosonicalio/enclave/internal/validationfx"
 )
 
+// exitCodeConfigError is the exit code used when the application configuration cannot be loaded or is invalid
+// (`EX_CONFIG` in sysexits.h), so orchestrators can tell misconfigurations apart from runtime failures.
+const exitCodeConfigError = 78
+
 // NewApplication creates a new enclave application with the provided options.
 //
 // It allocates the application with basic modules like application metadata (name, version
@@ -31,27 +50,29 @@ import (
 //
 // The returned application can be run using the [fx.App.Run] method, which will start the
 // application and manage its lifecycle.
+//
+// Configurations of every module are checked before building the application, instead of stopping at the first
+// invalid one. If any, the returned application fails with a [github.com/bosonicalio/enclave/config.Error] listing
+// every invalid environment variable (see [fx.App.Err]), and no module constructor is executed.
 func NewApplication(opts ...Option) *fx.App {
-	options := &option{
-		fxOpts: []fx.Option{
-			fx.RecoverFromPanics(),
-			applicationfx.Module,
-			loggingfx.ModuleSlog,
-		},
-	}
-	for _, opt := range opts {
-		opt(options)
-	}
-
-	if options.disableDepInjectorLogs {
-		options.fxOpts = append(options.fxOpts, fx.NopLogger)
-	}
-	return fx.New(options.fxOpts...)
+	return fx.New(newOptions(opts...).build(&fxevent.ConsoleLogger{W: os.Stderr})...)
 }
 
 // RunApplication initializes the application with the provided options.
 //
-// It loads environment variables from a .env file if it exists, uses OS environment variables otherwise.
+// It loads configuration values into environment variables from the following sources if they exist, in priority
+// order: OS environment variables, `.env` files (`ENCLAVE_DOTENV_FILES`, defaults to `.env`) and their
+// environment-specific variants (e.g. `.env.staging`, selected by `ENCLAVE_APP_ENVIRONMENT`), then an
+// environment-specific configuration file (e.g. `config.staging.yaml`) and a YAML, TOML or JSON configuration file
+// (`ENCLAVE_CONFIG_FILE`, defaults to `config.{yaml,yml,toml,json}`). Nested keys of configuration files are joined
+// with underscores into environment variable names (e.g. `http.server.address` sets `HTTP_SERVER_ADDRESS`).
+// The application exits with code 78 (`EX_CONFIG`) if any of these files is invalid, or if any module
+// configuration is invalid, printing a report of every invalid environment variable to the standard error.
+//
+// Secret configuration values (e.g. `SQL_CONNECTION_STRING`, `PAGE_TOKEN_CIPHER_KEY`) may reference secrets stored
+// in files (e.g. `file:///run/secrets/db`, or `file:///run/secrets/db.json#password` for a JSON object key),
+// resolved when module configurations are parsed. The AWS module adds Secrets Manager and Parameter Store
+// references.
 //
 // This routine is designed to be used as the entry point for the application, setting up the necessary dependencies
 // and configurations required for the application to run properly. It uses the [go.uber.org/fx] framework
@@ -61,11 +82,17 @@ func NewApplication(opts ...Option) *fx.App {
 // In addition, this routine sets up the application with basic modules like application metadata (name, version
 // and environment) and logging (with stdlib [slog] package).
 func RunApplication(opts ...Option) {
-	if err := godotenv.Load(); err != nil {
-		globallog.Logger().
-			Warn("failed to load .env file, using OS environment variables", slog.String("error", err.Error()))
+	if err := osenv.Load(); err != nil {
+		globallog.Logger().Error("failed to load configuration", slog.String("error", err.Error()))
+		os.Exit(exitCodeConfigError)
 	}
-	NewApplication(opts...).Run()
+	app := NewApplication(opts...)
+	var errConfig *osenv.Error
+	if errors.As(app.Err(), &errConfig) {
+		_ = errConfig.WriteReport(os.Stderr)
+		os.Exit(exitCodeConfigError)
+	}
+	app.Run()
 }
 
 // -- Testing --
@@ -84,25 +111,62 @@ func RunApplication(opts ...Option) {
 //
 // The returned application can be run using the [fx.App.Run] method, which will start the
 // application and manage its lifecycle.
+//
+// As in [NewApplication], configurations of every module are checked before building the application, failing `tb`
+// with every invalid environment variable. Dependency injector logs are written to `tb`, unless disabled.
 func NewTestApplication(tb testing.TB, opts ...Option) *fxtest.App {
+	return fxtest.New(tb, newOptions(opts...).build(fxtest.NewTestLogger(tb))...)
+}
+
+// -- Options --
+
+type option struct {
+	disableDepInjectorLogs bool
+	errorReporting         bool
+	fxOpts                 []fx.Option
+	configs                []config.Spec
+}
+
+// newOptions allocates the application options with basic modules (application metadata, logging and admin
+// server), then applies `opts`.
+func newOptions(opts ...Option) *option {
 	options := &option{
 		fxOpts: []fx.Option{
 			fx.RecoverFromPanics(),
 			applicationfx.Module,
 			loggingfx.ModuleSlog,
+			adminfx.Module,
 		},
+		configs: slices.Concat(applicationfx.Configs, loggingfx.Configs, adminfx.Configs),
 	}
 	for _, opt := range opts {
 		opt(options)
 	}
-	return fxtest.New(tb, options.fxOpts...)
+	return options
 }
 
-// -- Options --
+// build returns the fx options of the application, once module configurations are checked. If any is invalid, the
+// options fail the application with a [github.com/bosonicalio/enclave/config.Error] instead, so no module
+// constructor is executed. Dependency injector events are logged with `eventLogger`, unless disabled.
+func (o *option) build(eventLogger fxevent.Logger) []fx.Option {
+	if err := osenv.Check(o.configs...); err != nil {
+		failOpts := []fx.Option{fx.Error(err)}
+		if o.disableDepInjectorLogs {
+			failOpts = append(failOpts, fx.NopLogger)
+		}
+		return failOpts
+	}
 
-type option struct {
-	disableDepInjectorLogs bool
-	fxOpts                 []fx.Option
+	fxOpts := slices.Clone(o.fxOpts)
+	if o.errorReporting {
+		if o.disableDepInjectorLogs {
+			eventLogger = fxevent.NopLogger
+		}
+		fxOpts = append(fxOpts, fx.WithLogger(errorreportingfx.NewEventLogger(eventLogger)))
+	} else if o.disableDepInjectorLogs {
+		fxOpts = append(fxOpts, fx.NopLogger)
+	}
+	return fxOpts
 }
 
 // Option is a function that modifies the enclave application options.
@@ -116,26 +180,52 @@ func WithDisabledDepInjectorLogs() Option {
 }
 
 // WithFxOptions appends the provided fx options to the enclave application.
+//
+// Configurations parsed by these options are not checked before building the application; use [WithModule] to
+// register them.
 func WithFxOptions(opts ...fx.Option) Option {
 	return func(options *option) {
 		options.fxOpts = append(options.fxOpts, opts...)
 	}
 }
 
+// WithModule appends the provided fx module to the enclave application, along with the configurations it parses
+// (see [github.com/bosonicalio/enclave/config.SpecOf]), so they are checked before building the application.
+func WithModule(module fx.Option, configs ...config.Spec) Option {
+	return func(options *option) {
+		options.fxOpts = append(options.fxOpts, module)
+		options.configs = append(options.configs, configs...)
+	}
+}
+
+// WithSecretResolver registers `resolver` for secret references with `scheme` (e.g. `vault`) once the option is
+// applied to an application, replacing any previous one, so the application configurations may reference secrets
+// of a custom store (see [github.com/bosonicalio/enclave/config]).
+func WithSecretResolver(scheme string, resolver config.SecretResolver) Option {
+	return func(_ *option) {
+		osenv.RegisterSecretResolver(scheme, resolver)
+	}
+}
+
+// WithOptions groups `opts` into a single option, applied in order.
+func WithOptions(opts ...Option) Option {
+	return func(options *option) {
+		for _, opt := range opts {
+			opt(options)
+		}
+	}
+}
+
 // WithServerHTTP adds the HTTP server module to the enclave application.
 func WithServerHTTP() Option {
-	return WithFxOptions(
-		httpfx.ServerModule,
-	)
+	return WithModule(httpfx.ServerModule, httpfx.ServerConfigs...)
 }
 
 // WithValidation adds the validation module to the enclave application.
 //
 // This module provides a validation engine based on [github.com/go-playground/validator/v10].
 func WithValidation() Option {
-	return WithFxOptions(
-		validationfx.Module,
-	)
+	return WithModule(validationfx.Module, validationfx.Configs...)
 }
 
 // WithPersistence adds the persistence module to the enclave application.
@@ -143,9 +233,7 @@ func WithValidation() Option {
 // This module provides generic components for most persistence-related operations
 // (e.g. pagination API, identifier factory, transaction manager).
 func WithPersistence() Option {
-	return WithFxOptions(
-		persistencefx.Module,
-	)
+	return WithModule(persistencefx.Module, persistencefx.Configs...)
 }
 
 // WithSQL adds the SQL database module to the enclave application.
@@ -153,7 +241,82 @@ func WithPersistence() Option {
 // Requires an external module to provide the database connection (sql.DB). Available drivers are in different
 // go modules (e.g. enclave/postgres).
 func WithSQL() Option {
-	return WithFxOptions(
-		sqlfx.Module,
-	)
+	return WithModule(sqlfx.Module, sqlfx.Configs...)
+}
+
+// WithHealthChecks adds the health module to the enclave application.
+//
+// This module aggregates every health checker registered with [github.com/bosonicalio/enclave/health.AsChecker]
+// (built-in checkers are contributed by modules like SQL and S3) and exposes liveness and readiness endpoints
+// through the HTTP server module. Both endpoints are exempted from authentication (see [WithAuthentication]).
+func WithHealthChecks() Option {
+	return WithModule(healthfx.Module, healthfx.Configs...)
+}
+
+// WithRateLimiting adds the rate limiting module to the enclave application.
+//
+// This module limits the request rate of HTTP server clients, rejecting requests exceeding it with a
+// `429 Too Many Requests` status. Requires the HTTP server module (see [WithServerHTTP]) and, if configured
+// with the SQL store, the SQL module (see [WithSQL]).
+func WithRateLimiting() Option {
+	return WithModule(ratelimitfx.Module, ratelimitfx.Configs...)
+}
+
+// WithAuthentication adds the authentication module to the enclave application.
+//
+// This module verifies bearer JWTs sent to the HTTP server against static keys or a JSON Web Key Set endpoint,
+// placing the authenticated principal in the request context. Controllers declare per-route requirements with
+// [github.com/bosonicalio/enclave/authn.RequireAuthenticated] and similar middlewares. Requires the HTTP server
+// module (see [WithServerHTTP]).
+func WithAuthentication() Option {
+	return WithModule(authnfx.Module, authnfx.Configs...)
+}
+
+// WithAuthorization adds the authorization module to the enclave application.
+//
+// This module loads an authorization policy (role, scope and attribute rules) and provides an
+// [github.com/bosonicalio/enclave/authz.Authorizer] to be injected into services, so the same policy applies
+// outside the HTTP server. Routes declared by the policy are enforced by an HTTP server middleware, while
+// controllers may declare their own requirements with [github.com/bosonicalio/enclave/authz.Require].
+// Should be used along with the authentication module (see [WithAuthentication]).
+func WithAuthorization() Option {
+	return WithModule(authzfx.Module, authzfx.Configs...)
+}
+
+// WithTracing adds the distributed tracing module to the enclave application.
+//
+// This module provides an OpenTelemetry tracer provider exporting spans through OTLP. When present, HTTP server
+// requests, SQL statements and AWS SDK calls are traced automatically.
+func WithTracing() Option {
+	return WithModule(tracingfx.Module, tracingfx.Configs...)
+}
+
+// WithMetrics adds the metrics module to the enclave application.
+//
+// This module provides an OpenTelemetry meter provider exposing metrics in the Prometheus format, either through
+// the admin server (`ADMIN_ADDRESS`) or the HTTP server module. Go runtime metrics and an `app_info` gauge are
+// recorded by default; when present, the HTTP server and SQL modules record request and connection pool metrics.
+func WithMetrics() Option {
+	return WithModule(metricsfx.Module, metricsfx.Configs...)
+}
+
+// WithErrorReporting adds the error reporting module to the enclave application.
+//
+// This module reports errors to a Sentry-compatible service (`ERROR_REPORTING_DSN`) with their stack traces and
+// the application metadata. Panics recovered and 5xx responses committed by the HTTP server module are reported,
+// as well as failed lifecycle hooks (applications created with [NewApplication] only).
+func WithErrorReporting() Option {
+	return func(options *option) {
+		options.errorReporting = true
+		WithModule(errorreportingfx.Module, errorreportingfx.Configs...)(options)
+	}
+}
+
+// WithProfiling adds the profiling module to the enclave application.
+//
+// This module serves `net/http/pprof` handlers (CPU, heap, goroutine, block, mutex, etc.) on the admin server
+// (`ADMIN_ADDRESS`), protected by a token (`ADMIN_TOKEN`), separated from the HTTP server module. Optionally,
+// profiles are continuously pushed to a Pyroscope-compatible server (`PROFILING_PUSH_ENDPOINT`).
+func WithProfiling() Option {
+	return WithModule(profilingfx.Module, profilingfx.Configs...)
 }
@@ -0,0 +1,55 @@
+package enclave
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bosonicalio/enclave/config"
+)
+
+func TestNewApplication_ConfigErrors(t *testing.T) {
+	t.Setenv("ENCLAVE_APP_NAME", "")
+	t.Setenv("RATE_LIMIT_STORE", "redis")
+
+	app := NewApplication(
+		WithDisabledDepInjectorLogs(),
+		WithServerHTTP(),
+		WithRateLimiting(),
+	)
+	var errConfig *config.Error
+	require.True(t, errors.As(app.Err(), &errConfig), app.Err())
+	names := make([]string, 0, len(errConfig.Variables))
+	for _, variable := range errConfig.Variables {
+		names = append(names, variable.Name)
+	}
+	assert.Contains(t, names, "ENCLAVE_APP_NAME")
+	assert.Contains(t, names, "RATE_LIMIT_STORE")
+}
+
+type tbStub struct {
+	testing.TB
+	errors []string
+	failed bool
+}
+
+func (s *tbStub) Errorf(format string, args ...any) {
+	s.errors = append(s.errors, fmt.Sprintf(format, args...))
+}
+
+func (s *tbStub) FailNow() {
+	s.failed = true
+}
+
+func TestNewTestApplication_ConfigErrors(t *testing.T) {
+	t.Setenv("ENCLAVE_APP_NAME", "")
+
+	tb := &tbStub{TB: t}
+	NewTestApplication(tb, WithDisabledDepInjectorLogs())
+	assert.True(t, tb.failed)
+	require.Len(t, tb.errors, 1)
+	assert.Contains(t, tb.errors[0], "ENCLAVE_APP_NAME")
+}
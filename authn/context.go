@@ -0,0 +1,25 @@
+package authn
+
+import (
+	"context"
+
+	"github.com/bosonicalio/geck/security/identity"
+)
+
+type principalContextKey struct{}
+
+// WithPrincipal sets `principal` in `ctx`.
+//
+// The principal is also set as [identity.Principal] (see [identity.GetPrincipal]), so components unaware of
+// this package can still access it.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	ctx = identity.WithPrincipal(ctx, principal)
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext retrieves the authenticated [Principal] from `ctx`. Returns false if the request
+// was not authenticated.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
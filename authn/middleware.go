@@ -0,0 +1,62 @@
+package authn
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/geck/security/identity"
+	"github.com/bosonicalio/geck/syserr"
+)
+
+var (
+	// ErrUnauthenticated is returned when a request requires authentication but the client did not provide
+	// valid credentials.
+	ErrUnauthenticated = syserr.New(syserr.Unauthenticated, "authentication required",
+		syserr.WithInternalCode("UNAUTHENTICATED"),
+	)
+	// ErrInsufficientAuthorities is returned when the authenticated principal lacks the authorities
+	// required by a request.
+	ErrInsufficientAuthorities = syserr.New(syserr.PermissionDenied, "insufficient authorities",
+		syserr.WithInternalCode("INSUFFICIENT_AUTHORITIES"),
+	)
+)
+
+// RequireAuthenticated is an [echo.MiddlewareFunc] rejecting requests without an authenticated principal
+// with [ErrUnauthenticated].
+//
+// Use it to declare per-route requirements (e.g. `g.GET("/items", handler, authn.RequireAuthenticated())`).
+func RequireAuthenticated() echo.MiddlewareFunc {
+	return requirePrincipal(func(_ identity.Principal) bool {
+		return true
+	})
+}
+
+// RequireAnyAuthorities is an [echo.MiddlewareFunc] rejecting requests whose authenticated principal does
+// not have any of `authorities` with [ErrInsufficientAuthorities].
+func RequireAnyAuthorities(authorities ...string) echo.MiddlewareFunc {
+	return requirePrincipal(func(principal identity.Principal) bool {
+		return principal.HasAnyAuthorities(authorities...)
+	})
+}
+
+// RequireAllAuthorities is an [echo.MiddlewareFunc] rejecting requests whose authenticated principal does
+// not have every one of `authorities` with [ErrInsufficientAuthorities].
+func RequireAllAuthorities(authorities ...string) echo.MiddlewareFunc {
+	return requirePrincipal(func(principal identity.Principal) bool {
+		return principal.HasAllAuthorities(authorities...)
+	})
+}
+
+func requirePrincipal(isAllowed func(principal identity.Principal) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, err := identity.GetPrincipal(c.Request().Context())
+			if err != nil {
+				return ErrUnauthenticated
+			}
+			if !isAllowed(principal) {
+				return ErrInsufficientAuthorities
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package authn
+
+import (
+	"time"
+
+	"github.com/bosonicalio/geck/security/identity"
+)
+
+// Principal is an [identity.Principal] authenticated from a verified bearer token.
+type Principal struct {
+	identity.BasicPrincipal
+	// Issuer the entity that issued the token (`iss` claim).
+	Issuer string
+	// Audience the recipients the token is intended for (`aud` claim).
+	Audience []string
+	// ExpiresAt the expiration time of the token (`exp` claim). Zero if the token does not expire.
+	ExpiresAt time.Time
+	// Claims every claim of the token.
+	Claims map[string]any
+}
+
+// compile-time assertion
+var _ identity.Principal = (*Principal)(nil)
@@ -0,0 +1,181 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bosonicalio/geck/security/identity"
+	"github.com/bosonicalio/geck/syserr"
+
+	"github.com/bosonicalio/enclave/authn"
+)
+
+// ErrForbidden is returned when the principal is not authorized to perform an action over a resource.
+var ErrForbidden = syserr.New(syserr.PermissionDenied, "not authorized to perform this action",
+	syserr.WithInternalCode("FORBIDDEN"),
+)
+
+// Authorizer decides whether the principal in a context can perform an action over a resource.
+type Authorizer interface {
+	// Authorize returns nil if the principal in `ctx` (if any) can perform `action` over `resource`.
+	// Returns [authn.ErrUnauthenticated] if the request is anonymous and [ErrForbidden] otherwise.
+	Authorize(ctx context.Context, action, resource string) error
+}
+
+// Engine is an [Authorizer] evaluating a [Policy].
+//
+// Roles and scopes are read from the claims of an [authn.Principal]. For any other [identity.Principal],
+// its authorities are used as both roles and scopes.
+type Engine struct {
+	policy       Policy
+	rolesClaims  []string
+	scopesClaims []string
+}
+
+// compile-time assertion
+var _ Authorizer = (*Engine)(nil)
+
+// EngineOption configures an [Engine].
+type EngineOption func(*Engine)
+
+// WithRolesClaims sets the claims holding principal roles. Defaults to `roles`.
+func WithRolesClaims(claims ...string) EngineOption {
+	return func(e *Engine) {
+		e.rolesClaims = claims
+	}
+}
+
+// WithScopesClaims sets the claims holding principal scopes. Defaults to `scope` and `scp`.
+func WithScopesClaims(claims ...string) EngineOption {
+	return func(e *Engine) {
+		e.scopesClaims = claims
+	}
+}
+
+// NewEngine allocates a new [Engine] evaluating `policy`.
+func NewEngine(policy Policy, opts ...EngineOption) (*Engine, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	engine := &Engine{
+		policy:       policy,
+		rolesClaims:  []string{"roles"},
+		scopesClaims: []string{"scope", "scp"},
+	}
+	for _, opt := range opts {
+		opt(engine)
+	}
+	return engine, nil
+}
+
+// Authorize returns nil if the principal in `ctx` (if any) can perform `action` over `resource`.
+func (e *Engine) Authorize(ctx context.Context, action, resource string) error {
+	sub := e.newSubject(ctx)
+	attributes := ResourceAttributesFromContext(ctx)
+	allowed := false
+	for _, rule := range e.policy.Rules {
+		if !matchesAny(rule.Actions, action) || !matchesAny(rule.Resources, resource) ||
+			!sub.satisfies(rule, attributes) {
+			continue
+		}
+		if rule.Effect == EffectDeny {
+			allowed = false
+			break
+		}
+		allowed = true
+	}
+	switch {
+	case allowed:
+		return nil
+	case !sub.authenticated:
+		return authn.ErrUnauthenticated
+	default:
+		return ErrForbidden
+	}
+}
+
+// subject is the principal being authorized.
+type subject struct {
+	authenticated bool
+	roles         []string
+	scopes        []string
+	claims        map[string]any
+}
+
+func (e *Engine) newSubject(ctx context.Context) subject {
+	if principal, ok := authn.PrincipalFromContext(ctx); ok {
+		return subject{
+			authenticated: true,
+			roles:         claimValues(principal.Claims, e.rolesClaims),
+			scopes:        claimValues(principal.Claims, e.scopesClaims),
+			claims:        principal.Claims,
+		}
+	}
+	principal, err := identity.GetPrincipal(ctx)
+	if err != nil {
+		return subject{}
+	}
+	return subject{
+		authenticated: true,
+		roles:         principal.Authorities(),
+		scopes:        principal.Authorities(),
+		claims:        map[string]any{"sub": principal.ID()},
+	}
+}
+
+func (s subject) satisfies(rule Rule, resourceAttributes map[string]string) bool {
+	if (rule.Authenticated || len(rule.Roles) > 0 || len(rule.Scopes) > 0 || len(rule.Attributes) > 0) &&
+		!s.authenticated {
+		return false
+	}
+	if len(rule.Roles) > 0 && !containsAny(s.roles, rule.Roles) {
+		return false
+	}
+	if len(rule.Scopes) > 0 && !containsAny(s.scopes, rule.Scopes) {
+		return false
+	}
+	for claim, expected := range rule.Attributes {
+		if ref, ok := strings.CutPrefix(expected, "$"); ok {
+			var found bool
+			if expected, found = resourceAttributes[ref]; !found {
+				return false
+			}
+		}
+		if value, ok := s.claims[claim]; !ok || fmt.Sprint(value) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		for _, value := range values {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimValues collects values from `names` claims, either space-separated strings or string arrays.
+func claimValues(claims map[string]any, names []string) []string {
+	values := make([]string, 0)
+	for _, name := range names {
+		switch value := claims[name].(type) {
+		case string:
+			values = append(values, strings.Fields(value)...)
+		case []any:
+			for _, item := range value {
+				if str, ok := item.(string); ok {
+					values = append(values, str)
+				}
+			}
+		case []string:
+			values = append(values, value...)
+		}
+	}
+	return values
+}
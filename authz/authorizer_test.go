@@ -0,0 +1,118 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bosonicalio/geck/security/identity"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bosonicalio/enclave/authn"
+)
+
+const testPolicy = `
+rules:
+  - name: public catalog
+    actions: ["catalog:read"]
+    resources: ["*"]
+  - name: orders readers
+    actions: ["orders:read"]
+    resources: ["orders/*"]
+    scopes: ["orders:read"]
+  - name: orders owners
+    actions: ["orders:*"]
+    resources: ["orders/*"]
+    attributes:
+      tenant_id: $tenant_id
+  - name: admins
+    actions: ["*"]
+    resources: ["*"]
+    roles: ["admin"]
+  - name: locked orders
+    effect: deny
+    actions: ["orders:delete"]
+    resources: ["orders/locked"]
+routes:
+  - method: GET
+    path: /orders/:id
+    action: orders:read
+    resource: orders/:id
+`
+
+func newTestContext(subject string, claims map[string]any) context.Context {
+	claims["sub"] = subject
+	return authn.WithPrincipal(context.Background(), authn.Principal{
+		BasicPrincipal: identity.NewBasicPrincipal(subject),
+		Claims:         claims,
+	})
+}
+
+func TestEngine_Authorize(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	engine, err := NewEngine(policy)
+	require.NoError(t, err)
+
+	anonymous := context.Background()
+	reader := newTestContext("reader", map[string]any{"scope": "orders:read profile"})
+	admin := newTestContext("admin", map[string]any{"roles": []any{"admin"}})
+	owner := WithResourceAttributes(newTestContext("owner", map[string]any{"tenant_id": "acme"}),
+		map[string]string{"tenant_id": "acme"})
+
+	assert.NoError(t, engine.Authorize(anonymous, "catalog:read", "products/1"))
+	assert.Equal(t, authn.ErrUnauthenticated, engine.Authorize(anonymous, "orders:read", "orders/1"))
+	assert.NoError(t, engine.Authorize(reader, "orders:read", "orders/1"))
+	assert.Equal(t, ErrForbidden, engine.Authorize(reader, "orders:write", "orders/1"))
+	assert.NoError(t, engine.Authorize(owner, "orders:write", "orders/1"))
+	assert.NoError(t, engine.Authorize(admin, "orders:delete", "orders/1"))
+	assert.Equal(t, ErrForbidden, engine.Authorize(admin, "orders:delete", "orders/locked"))
+
+	// Tenant mismatch
+	other := WithResourceAttributes(newTestContext("owner", map[string]any{"tenant_id": "acme"}),
+		map[string]string{"tenant_id": "globex"})
+	assert.Equal(t, ErrForbidden, engine.Authorize(other, "orders:write", "orders/1"))
+}
+
+func TestParsePolicy(t *testing.T) {
+	_, err := ParsePolicy([]byte(`{"rules": [{"effect": "maybe", "actions": ["a"], "resources": ["b"]}]}`))
+	assert.Error(t, err)
+
+	_, err = ParsePolicy([]byte(`{"rules": [{"actions": ["a"]}]}`))
+	assert.Error(t, err)
+
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	route, found := policy.FindRoute(http.MethodGet, "/orders/:id")
+	assert.True(t, found)
+	assert.Equal(t, "orders:read", route.Action)
+	_, found = policy.FindRoute(http.MethodPost, "/orders/:id")
+	assert.False(t, found)
+}
+
+func TestRequire(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	engine, err := NewEngine(policy)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.GET("/tenants/:tenant_id/orders/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, Require(engine, "orders:write", "orders/:id"))
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/acme/orders/1", nil)
+	req = req.WithContext(newTestContext("owner", map[string]any{"tenant_id": "acme"}))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/tenants/globex/orders/1", nil)
+	req = req.WithContext(newTestContext("owner", map[string]any{"tenant_id": "acme"}))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.NotEqual(t, http.StatusNoContent, rec.Code)
+}
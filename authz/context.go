@@ -0,0 +1,17 @@
+package authz
+
+import "context"
+
+type resourceAttributesContextKey struct{}
+
+// WithResourceAttributes sets the attributes of the resource being authorized in `ctx`, so rules can compare
+// them with principal claims (see [Rule.Attributes]).
+func WithResourceAttributes(ctx context.Context, attributes map[string]string) context.Context {
+	return context.WithValue(ctx, resourceAttributesContextKey{}, attributes)
+}
+
+// ResourceAttributesFromContext retrieves the resource attributes set in `ctx` with [WithResourceAttributes].
+func ResourceAttributesFromContext(ctx context.Context) map[string]string {
+	attributes, _ := ctx.Value(resourceAttributesContextKey{}).(map[string]string)
+	return attributes
+}
@@ -0,0 +1,50 @@
+package authz
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Require is an [echo.MiddlewareFunc] authorizing `action` over `resource` with `authorizer` before
+// handling requests.
+//
+// Path parameters in `resource` (e.g. `orders/:id`) are replaced with their values, which are also set as
+// resource attributes (see [WithResourceAttributes]).
+//
+// Use it to declare per-route requirements (e.g. `g.GET("/orders/:id", handler, authz.Require(a, "orders:read", "orders/:id"))`).
+func Require(authorizer Authorizer, action, resource string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := AuthorizeRequest(c, authorizer, action, resource); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// AuthorizeRequest authorizes `action` over `resource` for the request of `c` with `authorizer`, expanding path
+// parameters as [Require] does.
+func AuthorizeRequest(c echo.Context, authorizer Authorizer, action, resource string) error {
+	names, values := c.ParamNames(), c.ParamValues()
+	attributes := make(map[string]string, len(names))
+	segments := strings.Split(resource, "/")
+	for i, name := range names {
+		if i >= len(values) {
+			break
+		}
+		attributes[name] = values[i]
+		for j, segment := range segments {
+			if segment == ":"+name {
+				segments[j] = values[i]
+			}
+		}
+	}
+	ctx := c.Request().Context()
+	if len(attributes) > 0 {
+		ctx = WithResourceAttributes(ctx, attributes)
+		c.SetRequest(c.Request().WithContext(ctx))
+	}
+	return authorizer.Authorize(ctx, action, strings.Join(segments, "/"))
+}
@@ -0,0 +1,128 @@
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Effect is the outcome of a [Rule] matching a request.
+type Effect string
+
+const (
+	// EffectAllow grants the request.
+	EffectAllow Effect = "allow"
+	// EffectDeny rejects the request. Deny rules take precedence over allow rules.
+	EffectDeny Effect = "deny"
+)
+
+// Policy is a set of authorization rules, along with the HTTP routes they protect.
+//
+// Requests not matching any allow rule are denied.
+type Policy struct {
+	// Rules the authorization rules.
+	Rules []Rule `json:"rules" yaml:"rules"`
+	// Routes maps HTTP routes to the action and resource authorized when they are requested.
+	Routes []Route `json:"routes" yaml:"routes"`
+}
+
+// Rule grants (or denies) a set of actions over a set of resources to principals matching its conditions.
+//
+// Every condition must be met for a rule to match. A rule without conditions matches every request,
+// including anonymous ones.
+type Rule struct {
+	// Name a human-readable identifier of the rule.
+	Name string `json:"name" yaml:"name"`
+	// Effect the effect of the rule. Defaults to [EffectAllow].
+	Effect Effect `json:"effect" yaml:"effect"`
+	// Actions the actions matched by the rule (e.g. `orders:read`). Supports [path.Match] patterns,
+	// `*` matches any action.
+	Actions []string `json:"actions" yaml:"actions"`
+	// Resources the resources matched by the rule (e.g. `orders/*`). Supports [path.Match] patterns,
+	// `*` matches any resource.
+	Resources []string `json:"resources" yaml:"resources"`
+	// Authenticated requires an authenticated principal.
+	Authenticated bool `json:"authenticated" yaml:"authenticated"`
+	// Roles requires the principal to have any of these roles.
+	Roles []string `json:"roles" yaml:"roles"`
+	// Scopes requires the principal to have any of these scopes.
+	Scopes []string `json:"scopes" yaml:"scopes"`
+	// Attributes requires principal claims to be equal to these values. Values starting with `$` refer to
+	// resource attributes (e.g. `tenant_id: $tenant_id`, see [WithResourceAttributes]).
+	Attributes map[string]string `json:"attributes" yaml:"attributes"`
+}
+
+// Route is an HTTP route protected by a [Policy].
+type Route struct {
+	// Method the HTTP method of the route. Empty or `*` matches any method.
+	Method string `json:"method" yaml:"method"`
+	// Path the route path, as registered in the HTTP server (e.g. `/v1/orders/:id`).
+	Path string `json:"path" yaml:"path"`
+	// Action the action authorized when the route is requested.
+	Action string `json:"action" yaml:"action"`
+	// Resource the resource authorized when the route is requested. Path parameters (e.g. `orders/:id`)
+	// are replaced with their values.
+	Resource string `json:"resource" yaml:"resource"`
+}
+
+// ParsePolicy parses a [Policy] from its YAML or JSON representation.
+func ParsePolicy(data []byte) (Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return Policy{}, fmt.Errorf("enclave.authz: invalid policy: %w", err)
+	}
+	return policy, policy.Validate()
+}
+
+// Validate checks every rule and route of the policy is well-formed.
+func (p Policy) Validate() error {
+	errs := make([]error, 0)
+	for i, rule := range p.Rules {
+		if rule.Effect != "" && rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+			errs = append(errs, fmt.Errorf("enclave.authz: rule %d has invalid effect %q", i, rule.Effect))
+		}
+		if len(rule.Actions) == 0 || len(rule.Resources) == 0 {
+			errs = append(errs, fmt.Errorf("enclave.authz: rule %d must declare actions and resources", i))
+		}
+		for _, pattern := range append(rule.Actions, rule.Resources...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("enclave.authz: rule %d has invalid pattern %q", i, pattern))
+			}
+		}
+	}
+	for i, route := range p.Routes {
+		if route.Path == "" || route.Action == "" || route.Resource == "" {
+			errs = append(errs, fmt.Errorf("enclave.authz: route %d must declare path, action and resource", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" {
+			return true
+		}
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FindRoute returns the route of the policy matching `method` and `routePath` (as registered in the HTTP server).
+func (p Policy) FindRoute(method, routePath string) (Route, bool) {
+	for _, route := range p.Routes {
+		if route.matches(method, routePath) {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+func (r Route) matches(method, routePath string) bool {
+	return (r.Method == "" || r.Method == "*" || strings.EqualFold(r.Method, method)) && r.Path == routePath
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 
 	"github.com/bosonicalio/enclave/aws/internal/awsconfig"
@@ -15,15 +17,34 @@ import (
 var module = fx.Module("enclave/aws",
 	fx.Provide(
 		osenv.ParseAs[awsconfig.Config],
-		func(cfg awsconfig.Config) (aws.Config, error) {
-			ctx := context.Background()
-			if cfg.Region != "local" {
-				return config.LoadDefaultConfig(ctx)
-			}
-			return config.LoadDefaultConfig(ctx,
-				config.WithRegion("us-east-1"),
-				config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("fakeAccess", "fakeSecret", "")),
-			)
-		},
+		fx.Annotate(
+			newConfig,
+			fx.ParamTags("", `optional:"true"`), // tracer provider is optional
+		),
 	),
 )
+
+// newConfig loads the AWS SDK configuration. SDK calls are traced if `tp` is not nil (i.e. the tracing module
+// is present).
+func newConfig(cfg awsconfig.Config, tp trace.TracerProvider) (aws.Config, error) {
+	ctx := context.Background()
+	var (
+		awsCfg aws.Config
+		err    error
+	)
+	if cfg.Region != "local" {
+		awsCfg, err = config.LoadDefaultConfig(ctx)
+	} else {
+		awsCfg, err = config.LoadDefaultConfig(ctx,
+			config.WithRegion("us-east-1"),
+			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("fakeAccess", "fakeSecret", "")),
+		)
+	}
+	if err != nil {
+		return aws.Config{}, err
+	}
+	if tp != nil {
+		otelaws.AppendMiddlewares(&awsCfg.APIOptions, otelaws.WithTracerProvider(tp))
+	}
+	return awsCfg, nil
+}
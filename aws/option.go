@@ -1,12 +1,27 @@
 package aws
 
 import (
+	"go.uber.org/fx"
+
 	"github.com/bosonicalio/enclave"
+	"github.com/bosonicalio/enclave/aws/internal/awsconfig"
+	"github.com/bosonicalio/enclave/config"
 )
 
 // WithAmazonWebServices returns an enclave.Option that includes the AWS module.
+//
+// Secret configuration values (e.g. `SQL_CONNECTION_STRING`) may reference AWS Secrets Manager secrets
+// (`awssm://<name>#<key>`) and Systems Manager Parameter Store parameters (`ssm:///<path>`), which are resolved
+// when parsing module configurations. Each option resolves them with its own AWS clients, which use the AWS SDK
+// configuration of its application once built (see [secretClients.use]). Before that (i.e. while checking and
+// building module configurations), they use a configuration loaded the same way without tracing.
 func WithAmazonWebServices() enclave.Option {
-	return enclave.WithFxOptions(
-		module,
+	clients := &secretClients{}
+	return enclave.WithOptions(
+		enclave.WithSecretResolver("awssm", config.SecretResolverFunc(clients.resolveSecretsManager)),
+		enclave.WithSecretResolver("ssm", config.SecretResolverFunc(clients.resolveParameterStore)),
+		enclave.WithModule(fx.Options(module, fx.Invoke(clients.use)),
+			config.SpecOf[awsconfig.Config](),
+		),
 	)
 }
@@ -0,0 +1,71 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// AutocertCache is an [autocert.Cache] implementation storing ACME certificates and account keys
+// in an S3 bucket, allowing multiple application replicas to share the same certificates.
+type AutocertCache struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// compile-time assertion
+var _ autocert.Cache = (*AutocertCache)(nil)
+
+// NewAutocertCache allocates a new [AutocertCache] storing objects in `bucket` under `prefix`.
+func NewAutocertCache(client *s3.Client, bucket, prefix string) AutocertCache {
+	return AutocertCache{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Get returns a certificate data for the specified key.
+// If there's no such key, Get returns [autocert.ErrCacheMiss].
+func (c AutocertCache) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := c.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(c.prefix + key),
+	})
+	var errNoSuchKey *types.NoSuchKey
+	if errors.As(err, &errNoSuchKey) {
+		return nil, autocert.ErrCacheMiss
+	} else if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+// Put stores the data in the cache under the specified key.
+func (c AutocertCache) Put(ctx context.Context, key string, data []byte) error {
+	_, err := c.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(c.bucket),
+		Key:                  aws.String(c.prefix + key),
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: types.ServerSideEncryptionAes256,
+	})
+	return err
+}
+
+// Delete removes a certificate data from the cache under the specified key.
+// If there's no such key in the cache, Delete returns nil.
+func (c AutocertCache) Delete(ctx context.Context, key string) error {
+	_, err := c.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(c.prefix + key),
+	})
+	return err
+}
@@ -0,0 +1,10 @@
+package s3
+
+type config struct {
+	HealthCheckBucket string `env:"AWS_S3_HEALTH_CHECK_BUCKET"`
+}
+
+type autocertCacheConfig struct {
+	Bucket string `env:"AWS_S3_AUTOCERT_CACHE_BUCKET" validate:"required"`
+	Prefix string `env:"AWS_S3_AUTOCERT_CACHE_PREFIX" envDefault:"autocert/"`
+}
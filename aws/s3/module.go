@@ -1,16 +1,22 @@
 package s3
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/samber/lo"
 	"go.uber.org/fx"
+	"golang.org/x/crypto/acme/autocert"
 
 	"github.com/bosonicalio/enclave/aws/internal/awsconfig"
+	"github.com/bosonicalio/enclave/health"
+	"github.com/bosonicalio/enclave/internal/osenv"
 )
 
 var module = fx.Module("enclave/aws",
 	fx.Provide(
+		osenv.ParseAs[config],
 		func(baseCfg awsconfig.Config, awsCfg aws.Config) *s3.Client {
 			return s3.NewFromConfig(awsCfg, func(options *s3.Options) {
 				if baseCfg.Region != "local" {
@@ -20,5 +26,39 @@ var module = fx.Module("enclave/aws",
 				options.BaseEndpoint = lo.EmptyableToPtr(baseCfg.EndpointURL)
 			})
 		},
+		health.AsChecker(newHealthChecker),
+	),
+)
+
+var autocertCacheModule = fx.Module("enclave/aws/s3/autocert",
+	fx.Provide(
+		osenv.ParseAs[autocertCacheConfig],
+		fx.Annotate(
+			newAutocertCache,
+			fx.As(new(autocert.Cache)),
+		),
 	),
 )
+
+// -- Factory --
+
+// newHealthChecker checks the configured health check bucket is reachable. If no bucket is set,
+// it falls back to listing buckets, which requires the `s3:ListAllMyBuckets` permission.
+func newHealthChecker(cfg config, client *s3.Client) health.Checker {
+	return health.NewChecker("s3", func(ctx context.Context) error {
+		if cfg.HealthCheckBucket != "" {
+			_, err := client.HeadBucket(ctx, &s3.HeadBucketInput{
+				Bucket: aws.String(cfg.HealthCheckBucket),
+			})
+			return err
+		}
+		_, err := client.ListBuckets(ctx, &s3.ListBucketsInput{
+			MaxBuckets: aws.Int32(1),
+		})
+		return err
+	})
+}
+
+func newAutocertCache(cfg autocertCacheConfig, client *s3.Client) AutocertCache {
+	return NewAutocertCache(client, cfg.Bucket, cfg.Prefix)
+}
@@ -2,11 +2,23 @@ package s3
 
 import (
 	"github.com/bosonicalio/enclave"
+	enclaveconfig "github.com/bosonicalio/enclave/config"
 )
 
 // WithS3 returns an enclave.Option that includes the S3 module.
 func WithS3() enclave.Option {
-	return enclave.WithFxOptions(
-		module,
+	return enclave.WithModule(module,
+		enclaveconfig.SpecOf[config](),
+	)
+}
+
+// WithAutocertCache returns an enclave.Option that stores HTTP server ACME (auto TLS) certificates in S3,
+// sharing them between application replicas.
+//
+// Requires the S3 module (see [WithS3]). The bucket is set with the `AWS_S3_AUTOCERT_CACHE_BUCKET`
+// environment variable.
+func WithAutocertCache() enclave.Option {
+	return enclave.WithModule(autocertCacheModule,
+		enclaveconfig.SpecOf[autocertCacheConfig](),
 	)
 }
@@ -0,0 +1,104 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/samber/lo"
+
+	"github.com/bosonicalio/enclave/aws/internal/awsconfig"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// secretClients resolves configuration secrets with AWS services:
+//
+//   - `awssm://<name>#<key>`: AWS Secrets Manager secret (use `awssm:///<arn>` for ARNs).
+//   - `ssm:///<path>`: AWS Systems Manager Parameter Store parameter, decrypted if it is a `SecureString`.
+type secretClients struct {
+	mu             sync.Mutex
+	secretsManager *secretsmanager.Client
+	parameterStore *ssm.Client
+}
+
+// use makes resolvers use `awsCfg`, the AWS SDK configuration of the AWS module.
+func (c *secretClients) use(cfg awsconfig.Config, awsCfg aws.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setClients(cfg, awsCfg)
+}
+
+func (c *secretClients) setClients(cfg awsconfig.Config, awsCfg aws.Config) {
+	var endpoint *string
+	if cfg.Region == "local" {
+		endpoint = lo.EmptyableToPtr(cfg.EndpointURL)
+	}
+	c.secretsManager = secretsmanager.NewFromConfig(awsCfg, func(options *secretsmanager.Options) {
+		options.BaseEndpoint = endpoint
+	})
+	c.parameterStore = ssm.NewFromConfig(awsCfg, func(options *ssm.Options) {
+		options.BaseEndpoint = endpoint
+	})
+}
+
+// clients returns the resolver clients, loading the AWS SDK configuration if the AWS module was not built yet.
+//
+// The AWS module configuration has no secret fields, so it is parsed without resolving any secret reference
+// (i.e. without calling resolvers recursively).
+func (c *secretClients) clients() (*secretsmanager.Client, *ssm.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.secretsManager != nil {
+		return c.secretsManager, c.parameterStore, nil
+	}
+	cfg, err := osenv.ParseAs[awsconfig.Config]()
+	if err != nil {
+		return nil, nil, err
+	}
+	awsCfg, err := newConfig(cfg, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.setClients(cfg, awsCfg)
+	return c.secretsManager, c.parameterStore, nil
+}
+
+func (c *secretClients) resolveSecretsManager(ctx context.Context, ref *url.URL) (string, error) {
+	client, _, err := c.clients()
+	if err != nil {
+		return "", err
+	}
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(strings.TrimPrefix(ref.Host+ref.Path, "/")),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil {
+		return string(out.SecretBinary), nil
+	}
+	return *out.SecretString, nil
+}
+
+func (c *secretClients) resolveParameterStore(ctx context.Context, ref *url.URL) (string, error) {
+	_, client, err := c.clients()
+	if err != nil {
+		return "", err
+	}
+	out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ref.Host + ref.Path),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.Parameter == nil || out.Parameter.Value == nil {
+		return "", errors.New("parameter has no value")
+	}
+	return *out.Parameter.Value, nil
+}
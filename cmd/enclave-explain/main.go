@@ -0,0 +1,39 @@
+// Command enclave-explain lists the environment variables read by the built-in enclave modules, along with their
+// type, default value, validation rules and current (redacted) value. It can also emit a `.env.example` file or a
+// Kubernetes ConfigMap skeleton.
+//
+// Usage:
+//
+//	enclave-explain [-format table|dotenv|configmap] [-name NAME]
+//
+// Modules of other Go modules (e.g. Postgres, AWS) are not included; applications may expose the same command
+// with their own options through [enclave.RunExplain].
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bosonicalio/enclave"
+)
+
+func main() {
+	err := enclave.RunExplain(os.Args[1:], os.Stdout,
+		enclave.WithServerHTTP(),
+		enclave.WithHealthChecks(),
+		enclave.WithValidation(),
+		enclave.WithPersistence(),
+		enclave.WithSQL(),
+		enclave.WithRateLimiting(),
+		enclave.WithTracing(),
+		enclave.WithMetrics(),
+		enclave.WithProfiling(),
+		enclave.WithAuthentication(),
+		enclave.WithAuthorization(),
+		enclave.WithErrorReporting(),
+	)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,86 @@
+// Package config parses application configuration structures from environment variables, the same way enclave
+// modules do.
+//
+// Structures declare their variables with `env` struct tags (see [github.com/caarlos0/env/v11]), including nested
+// structures with `envPrefix` tags, and their constraints with `validate` struct tags (see
+// [github.com/go-playground/validator/v10]). Values of fields tagged with `secret:"true"` are secrets: they may
+// reference secret stores (e.g. `file:///run/secrets/db`) and are redacted when described.
+//
+//	type Config struct {
+//		Endpoint string        `env:"PAYMENTS_ENDPOINT" validate:"required,url"`
+//		APIKey   string        `env:"PAYMENTS_API_KEY" validate:"required" secret:"true"`
+//		Timeout  time.Duration `env:"PAYMENTS_TIMEOUT" envDefault:"5s" validate:"gt=0"`
+//		Database struct {
+//			Host string `env:"HOST" validate:"required"`
+//		} `envPrefix:"PAYMENTS_DB_"`
+//	}
+package config
+
+import (
+	"reflect"
+
+	"go.uber.org/fx"
+
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Error is the aggregated error of a configuration, listing every invalid environment variable (e.g. missing,
+// malformed or failing validation) instead of stopping at the first one.
+type Error = osenv.Error
+
+// VariableError is an invalid environment variable, identified by its name.
+type VariableError = osenv.VariableError
+
+// SecretResolver resolves the value of secret references of a URI scheme (e.g. `vault://orders/db`), once
+// registered (see [github.com/bosonicalio/enclave.WithSecretResolver]).
+type SecretResolver = osenv.SecretResolver
+
+// SecretResolverFunc is a function implementing [SecretResolver].
+type SecretResolverFunc = osenv.SecretResolverFunc
+
+type options struct {
+	prefix string
+}
+
+// Option configures how a configuration structure is parsed.
+type Option func(*options)
+
+// WithPrefix prepends `prefix` to every environment variable name of the structure (e.g. `ORDERS_` loads
+// `DB_HOST` from `ORDERS_DB_HOST`), so the same structure can be parsed for several components.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// Spec is a configuration structure type along with its parsing options, used to check and describe it without
+// parsing it (e.g. see [github.com/bosonicalio/enclave.WithModule]).
+type Spec = osenv.ConfigSpec
+
+// SpecOf returns the [Spec] of a `T` structure parsed with `opts`.
+func SpecOf[T any](opts ...Option) Spec {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return Spec{Type: reflect.TypeFor[T](), Prefix: o.prefix}
+}
+
+// Parse parses a `T` structure from environment variables and validates it.
+//
+// Returns an [*Error] listing every invalid environment variable.
+func Parse[T any](opts ...Option) (T, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return osenv.ParseAsWithPrefix[T](o.prefix)
+}
+
+// Provide returns an `uber/fx` option providing a `T` structure parsed with [Parse], failing the application
+// startup if the configuration is invalid.
+func Provide[T any](opts ...Option) fx.Option {
+	return fx.Provide(func() (T, error) {
+		return Parse[T](opts...)
+	})
+}
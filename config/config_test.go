@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type databaseConfig struct {
+	Host string `env:"HOST" validate:"required"`
+	Port int    `env:"PORT" envDefault:"5432" validate:"gt=0"`
+}
+
+type paymentsConfig struct {
+	Endpoint string         `env:"PAYMENTS_ENDPOINT" validate:"required,url"`
+	Timeout  time.Duration  `env:"PAYMENTS_TIMEOUT" envDefault:"5s"`
+	Mode     string         `env:"PAYMENTS_MODE" envDefault:"live" validate:"oneof=live sandbox"`
+	Database databaseConfig `envPrefix:"PAYMENTS_DB_"`
+}
+
+func TestParse(t *testing.T) {
+	t.Setenv("ORDERS_PAYMENTS_ENDPOINT", "https://payments.example.com")
+	t.Setenv("ORDERS_PAYMENTS_DB_HOST", "localhost")
+
+	cfg, err := Parse[paymentsConfig](WithPrefix("ORDERS_"))
+	require.NoError(t, err)
+	assert.Equal(t, paymentsConfig{
+		Endpoint: "https://payments.example.com",
+		Timeout:  5 * time.Second,
+		Mode:     "live",
+		Database: databaseConfig{Host: "localhost", Port: 5432},
+	}, cfg)
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Setenv("PAYMENTS_ENDPOINT", "not a url")
+	t.Setenv("PAYMENTS_TIMEOUT", "forever")
+	t.Setenv("PAYMENTS_MODE", "test")
+	t.Setenv("PAYMENTS_DB_PORT", "0")
+
+	_, err := Parse[paymentsConfig]()
+	var errConfig *Error
+	require.True(t, errors.As(err, &errConfig))
+	assert.ElementsMatch(t, []VariableError{
+		{Name: "PAYMENTS_TIMEOUT", Message: `is not a valid time.Duration: unable to parse duration: time: invalid duration "forever"`},
+		{Name: "PAYMENTS_ENDPOINT", Message: "must be a valid URL"},
+		{Name: "PAYMENTS_MODE", Message: "must be one of: live, sandbox"},
+		{Name: "PAYMENTS_DB_HOST", Message: "is required"},
+		{Name: "PAYMENTS_DB_PORT", Message: "must be greater than 0"},
+	}, errConfig.Variables)
+}
+
+func TestProvide(t *testing.T) {
+	t.Setenv("PAYMENTS_ENDPOINT", "https://payments.example.com")
+	t.Setenv("PAYMENTS_DB_HOST", "localhost")
+
+	var cfg paymentsConfig
+	app := fxtest.New(t,
+		Provide[paymentsConfig](),
+		fx.Populate(&cfg),
+	)
+	app.RequireStart().RequireStop()
+	assert.Equal(t, "https://payments.example.com", cfg.Endpoint)
+}
@@ -0,0 +1,219 @@
+package enclave
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/bosonicalio/enclave/internal/loghandler"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Variable describes an environment variable read by an enclave application.
+type Variable struct {
+	// Name is the environment variable name (e.g. `HTTP_SERVER_ADDRESS`).
+	Name string
+	// Type is the Go type of the value (e.g. `time.Duration`).
+	Type string
+	// Default is the value used if the variable is not set.
+	Default    string
+	HasDefault bool
+	// Required indicates the variable must be set.
+	Required bool
+	// Rules are the validation rules of the value (see [github.com/go-playground/validator/v10]).
+	Rules string
+	// Sensitive indicates the variable holds a secret, either declared by its configuration (`secret:"true"` struct
+	// tag) or named after a key redacted from logs (`LOG_REDACT_KEYS`, e.g. `AUTH_JWT_HMAC_SECRET`).
+	Sensitive bool
+	// Value is the current value, [Variable.Sensitive] ones are redacted.
+	Value string
+	// IsSet indicates the variable is set in the current environment.
+	IsSet bool
+	// Error describes why the current value is invalid, if so.
+	Error string
+}
+
+// Explain lists every environment variable read by an application built with `opts` (see [NewApplication]), along
+// with their current value in the environment (redacted if sensitive) and why it is invalid, if so.
+//
+// Variables are described from the configurations registered by the modules of `opts` (see [WithModule]), without
+// building the application.
+func Explain(opts ...Option) []Variable {
+	options := newOptions(opts...)
+	invalid := make(map[string]string)
+	var errConfig *osenv.Error
+	if errors.As(osenv.Check(options.configs...), &errConfig) {
+		for _, variable := range errConfig.Variables {
+			invalid[variable.Name] = variable.Message
+		}
+	}
+
+	keys := redactKeys()
+	variables := make([]Variable, 0)
+	seen := make(map[string]struct{})
+	for _, cfg := range options.configs {
+		for _, spec := range osenv.Describe(cfg.Type, cfg.Prefix) {
+			if _, ok := seen[spec.Name]; ok {
+				continue
+			}
+			seen[spec.Name] = struct{}{}
+			variables = append(variables, newVariable(spec, keys, invalid[spec.Name]))
+		}
+	}
+	return variables
+}
+
+// redactKeys returns the keys redacted from logs (`LOG_REDACT_KEYS`), falling back to the default ones if the
+// logging configuration is invalid.
+func redactKeys() []string {
+	if cfg, err := osenv.ParseAs[loghandler.Config](); err == nil {
+		return cfg.RedactKeys
+	}
+	f, _ := reflect.TypeFor[loghandler.Config]().FieldByName("RedactKeys")
+	return strings.Split(f.Tag.Get("envDefault"), ",")
+}
+
+func newVariable(spec osenv.VariableSpec, redactKeys []string, errMsg string) Variable {
+	value, isSet := os.LookupEnv(spec.Name)
+	// keys are matched against the underscore-separated parts of the name (e.g. `api_key` in `PAYMENTS_API_KEY`)
+	name := "_" + strings.ToLower(spec.Name) + "_"
+	sensitive := spec.Secret || slices.ContainsFunc(redactKeys, func(key string) bool {
+		key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+		return key != "" && strings.Contains(name, "_"+key+"_")
+	})
+	if sensitive && value != "" {
+		value = loghandler.RedactedValue
+	}
+	return Variable{
+		Name:       spec.Name,
+		Type:       spec.Type,
+		Default:    spec.Default,
+		HasDefault: spec.HasDefault,
+		Required:   spec.Required,
+		Rules:      spec.Rules,
+		Sensitive:  sensitive,
+		Value:      value,
+		IsSet:      isSet,
+		Error:      errMsg,
+	}
+}
+
+// -- Output formats --
+
+// WriteVariables writes `variables` to `w` as a table.
+func WriteVariables(w io.Writer, variables []Variable) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(table, "VARIABLE\tTYPE\tDEFAULT\tRULES\tVALUE\tERROR")
+	for _, v := range variables {
+		value := v.Value
+		if !v.IsSet {
+			value = "-"
+		}
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			v.Name, v.Type, truncate(orDash(v.Default)), orDash(v.Rules), truncate(value), orDash(v.Error))
+	}
+	return table.Flush()
+}
+
+// WriteDotEnvExample writes `variables` to `w` as a `.env.example` file, set to their default values and
+// documented with their type and validation rules.
+func WriteDotEnvExample(w io.Writer, variables []Variable) error {
+	for _, v := range variables {
+		description := v.Type
+		if v.Required {
+			description += ", required"
+		}
+		if v.Rules != "" {
+			description += " (" + v.Rules + ")"
+		}
+		if _, err := fmt.Fprintf(w, "# %s\n%s=%s\n\n", description, v.Name, v.Default); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteConfigMap writes `variables` to `w` as a Kubernetes ConfigMap manifest named `name`, set to their default
+// values. Sensitive variables are left commented out, as they should be set from a Secret instead.
+func WriteConfigMap(w io.Writer, name string, variables []Variable) error {
+	if _, err := fmt.Fprintf(w, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\ndata:\n", name); err != nil {
+		return err
+	}
+	for _, v := range variables {
+		line := fmt.Sprintf("  %s: %s\n", v.Name, strconv.Quote(v.Default))
+		if v.Sensitive {
+			line = fmt.Sprintf("  # %s: set from a Secret\n", v.Name)
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// _maxColumnLength is the maximum length of values in tables (in runes), longer ones are truncated.
+const _maxColumnLength = 40
+
+func truncate(s string) string {
+	if utf8.RuneCountInString(s) <= _maxColumnLength {
+		return s
+	}
+	return string([]rune(s)[:_maxColumnLength-3]) + "..."
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// -- Command --
+
+// RunExplain runs the `explain` command with `args` (e.g. `os.Args[2:]`), writing the environment variables read
+// by an application built with `opts` to `w` (see [Explain]).
+//
+// Flags:
+//
+//	-format string  output format: table, dotenv (.env.example file) or configmap (Kubernetes manifest) (default "table")
+//	-name string    name of the ConfigMap (default "app")
+//
+// Applications may expose it as a subcommand:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "explain" {
+//		if err := enclave.RunExplain(os.Args[2:], os.Stdout, opts...); err != nil {
+//			log.Fatal(err)
+//		}
+//		return
+//	}
+//	enclave.RunApplication(opts...)
+func RunExplain(args []string, w io.Writer, opts ...Option) error {
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	format := flags.String("format", "table", "output format: table, dotenv (.env.example file) or configmap (Kubernetes manifest)")
+	name := flags.String("name", "app", "name of the ConfigMap")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := osenv.Load(); err != nil {
+		return err
+	}
+	variables := Explain(opts...)
+	switch *format {
+	case "table":
+		return WriteVariables(w, variables)
+	case "dotenv":
+		return WriteDotEnvExample(w, variables)
+	case "configmap":
+		return WriteConfigMap(w, *name, variables)
+	default:
+		return fmt.Errorf("enclave: unknown explain format %q", *format)
+	}
+}
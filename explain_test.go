@@ -0,0 +1,100 @@
+package enclave
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bosonicalio/enclave/internal/loghandler"
+)
+
+func TestExplain(t *testing.T) {
+	t.Setenv("ENCLAVE_APP_NAME", "")
+	t.Setenv("HEALTH_CHECK_TIMEOUT", "3s")
+	t.Setenv("AUTH_JWT_HMAC_SECRET", "0123456789abcdef0123456789abcdef")
+
+	variables := Explain(WithHealthChecks(), WithServerHTTP(), WithAuthentication())
+	byName := make(map[string]Variable, len(variables))
+	for _, v := range variables {
+		byName[v.Name] = v
+	}
+
+	assert.Equal(t, Variable{
+		Name:     "ENCLAVE_APP_NAME",
+		Type:     "string",
+		Required: true,
+		Rules:    "required",
+		IsSet:    true,
+		Error:    "is required",
+	}, byName["ENCLAVE_APP_NAME"])
+	assert.Equal(t, Variable{
+		Name:       "HEALTH_CHECK_TIMEOUT",
+		Type:       "time.Duration",
+		Default:    "5s",
+		HasDefault: true,
+		Rules:      "gt=0",
+		Value:      "3s",
+		IsSet:      true,
+	}, byName["HEALTH_CHECK_TIMEOUT"])
+	assert.True(t, byName["AUTH_JWT_HMAC_SECRET"].Sensitive)
+	assert.Equal(t, loghandler.RedactedValue, byName["AUTH_JWT_HMAC_SECRET"].Value)
+	assert.True(t, byName["HTTP_SERVER_TLS_KEY"].Sensitive)
+	assert.False(t, byName["HTTP_SERVER_TLS_KEY_FILE"].Sensitive)
+	assert.False(t, byName["HTTP_SERVER_ADDRESS"].Sensitive)
+	assert.Contains(t, byName, "HTTP_SERVER_ADDRESS")
+	assert.NotContains(t, byName, "SQL_CONNECTION_STRING")
+
+	// modules are not built, so missing dependencies (e.g. the database connection) do not matter
+	variables = Explain(WithSQL())
+	assert.True(t, slices.ContainsFunc(variables, func(v Variable) bool {
+		return v.Name == "SQL_CONNECTION_STRING" && v.Error == "is required"
+	}))
+}
+
+func TestWriteVariables(t *testing.T) {
+	variables := []Variable{
+		{Name: "HTTP_SERVER_ADDRESS", Type: "string", Default: ":8080", HasDefault: true},
+		{Name: "AUTH_JWT_HMAC_SECRET", Type: "string", Sensitive: true},
+		{Name: "ENCLAVE_APP_NAME", Type: "string", Required: true, Rules: "required"},
+	}
+
+	dotEnv := &bytes.Buffer{}
+	require.NoError(t, WriteDotEnvExample(dotEnv, variables))
+	assert.Equal(t, `# string
+HTTP_SERVER_ADDRESS=:8080
+
+# string
+AUTH_JWT_HMAC_SECRET=
+
+# string, required (required)
+ENCLAVE_APP_NAME=
+
+`, dotEnv.String())
+
+	configMap := &bytes.Buffer{}
+	require.NoError(t, WriteConfigMap(configMap, "orders", variables))
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: orders
+data:
+  HTTP_SERVER_ADDRESS: ":8080"
+  # AUTH_JWT_HMAC_SECRET: set from a Secret
+  ENCLAVE_APP_NAME: ""
+`, configMap.String())
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short"))
+	assert.Equal(t, strings.Repeat("a", 37)+"...", truncate(strings.Repeat("a", 50)))
+	// truncated by rune, so multibyte characters are kept whole
+	assert.Equal(t, strings.Repeat("ñ", 40), truncate(strings.Repeat("ñ", 40)))
+	truncated := truncate(strings.Repeat("日本", 30))
+	assert.Equal(t, strings.Repeat("日本", 18)+"日...", truncated)
+	assert.True(t, utf8.ValidString(truncated))
+}
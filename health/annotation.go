@@ -0,0 +1,20 @@
+package health
+
+import (
+	"go.uber.org/fx"
+)
+
+// AsChecker annotates `t` (preferred a builder routine) as a [Checker] and adds it to the health
+// checker registry.
+//
+// The health checker registry is used by the enclave health module, which will call each of
+// the registered [Checker.Check] routines when the readiness endpoint is requested.
+//
+// This annotation only works for `uber/fx` providers.
+func AsChecker(t any) any {
+	return fx.Annotate(
+		t,
+		fx.As(new(Checker)),
+		fx.ResultTags(`group:"health_checkers"`),
+	)
+}
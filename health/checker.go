@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+)
+
+// Checker is a component reporting whether a single dependency of the application (e.g. a database, an
+// object storage bucket) is usable.
+//
+// Checkers are aggregated by the enclave health module and exposed through the readiness endpoint.
+type Checker interface {
+	// Name returns the unique name of the dependency being checked (e.g. `postgres`, `s3`).
+	Name() string
+	// Check verifies the dependency is usable. Returns a non-nil error if the dependency is not usable.
+	Check(ctx context.Context) error
+}
+
+// CheckFunc is a routine verifying a dependency is usable.
+type CheckFunc func(ctx context.Context) error
+
+type checkerFunc struct {
+	name string
+	fn   CheckFunc
+}
+
+// compile-time assertion
+var _ Checker = (*checkerFunc)(nil)
+
+// NewChecker allocates a [Checker] named `name` using `fn` as check routine.
+func NewChecker(name string, fn CheckFunc) Checker {
+	return checkerFunc{
+		name: name,
+		fn:   fn,
+	}
+}
+
+func (c checkerFunc) Name() string {
+	return c.name
+}
+
+func (c checkerFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
@@ -0,0 +1,25 @@
+package health
+
+// Status is the health status of an application or one of its dependencies.
+type Status string
+
+const (
+	// StatusUp indicates the application or dependency is usable.
+	StatusUp Status = "up"
+	// StatusDown indicates the application or dependency is not usable.
+	StatusDown Status = "down"
+)
+
+// Report is the aggregated health state of an application.
+type Report struct {
+	// Status top level status. It is [StatusDown] if any of the checks failed.
+	Status Status `json:"status"`
+	// Checks results of each [Checker] keyed by [Checker.Name].
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is the health state of a single dependency.
+type CheckResult struct {
+	Status Status `json:"status"`
+	Took   string `json:"took"`
+}
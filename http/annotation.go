@@ -1,6 +1,10 @@
 package http
 
 import (
+	"errors"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
 
 	geckhttp "github.com/bosonicalio/geck/transport/http"
@@ -21,3 +25,64 @@ func AsController(t any) any {
 		fx.ResultTags(`group:"http_controllers"`),
 	)
 }
+
+var (
+	_middlewareFuncType = reflect.TypeOf(echo.MiddlewareFunc(nil))
+	_middlewareType     = reflect.TypeOf(Middleware{})
+	_errorType          = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// AsMiddleware annotates `t` as a [Middleware] with the given `priority` and adds it to the HTTP
+// middleware registry.
+//
+// `t` is either an [echo.MiddlewareFunc] or a builder routine returning an [echo.MiddlewareFunc] (and
+// optionally an error) whose arguments are resolved by the dependency injector. Panics if `t` is none of them.
+//
+// The HTTP middleware registry is used by the HTTP server module, which applies every registered
+// middleware to the server before exposing controller endpoints. See [Middleware] for ordering rules.
+//
+// This annotation only works for `uber/fx` providers.
+func AsMiddleware(t any, priority int) any {
+	fnVal := reflect.ValueOf(t)
+	fnType := fnVal.Type()
+	if fnType.ConvertibleTo(_middlewareFuncType) {
+		mw := fnVal.Convert(_middlewareFuncType).Interface().(echo.MiddlewareFunc)
+		t = func() echo.MiddlewareFunc {
+			return mw
+		}
+		fnVal = reflect.ValueOf(t)
+		fnType = fnVal.Type()
+	}
+	if fnType.Kind() != reflect.Func || fnType.NumOut() == 0 || fnType.NumOut() > 2 ||
+		!fnType.Out(0).ConvertibleTo(_middlewareFuncType) || (fnType.NumOut() == 2 && fnType.Out(1) != _errorType) {
+		panic(errors.New("enclave.http: middleware must be an echo.MiddlewareFunc or a routine " +
+			"returning an echo.MiddlewareFunc (and optionally an error)"))
+	}
+
+	in := make([]reflect.Type, 0, fnType.NumIn())
+	for i := 0; i < fnType.NumIn(); i++ {
+		in = append(in, fnType.In(i))
+	}
+	builderType := reflect.FuncOf(in, []reflect.Type{_middlewareType, _errorType}, fnType.IsVariadic())
+	builder := reflect.MakeFunc(builderType, func(args []reflect.Value) []reflect.Value {
+		var results []reflect.Value
+		if fnType.IsVariadic() {
+			results = fnVal.CallSlice(args)
+		} else {
+			results = fnVal.Call(args)
+		}
+		errVal := reflect.Zero(_errorType)
+		if len(results) == 2 && !results[1].IsNil() {
+			return []reflect.Value{reflect.Zero(_middlewareType), results[1]}
+		}
+		mw := Middleware{
+			Func:     results[0].Convert(_middlewareFuncType).Interface().(echo.MiddlewareFunc),
+			Priority: priority,
+		}
+		return []reflect.Value{reflect.ValueOf(mw), errVal}
+	})
+	return fx.Annotate(
+		builder.Interface(),
+		fx.ResultTags(`group:"http_middlewares"`),
+	)
+}
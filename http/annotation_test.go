@@ -0,0 +1,54 @@
+package http
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type middlewareRegistry struct {
+	fx.In
+	Middlewares []Middleware `group:"http_middlewares"`
+}
+
+func TestAsMiddleware(t *testing.T) {
+	noopMiddleware := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+
+	// Not a middleware
+	assert.Panics(t, func() { AsMiddleware("foo", 0) })
+	assert.Panics(t, func() { AsMiddleware(func() string { return "" }, 0) })
+
+	// Plain middleware and builders
+	var registry middlewareRegistry
+	app := fxtest.New(t,
+		fx.Supply("prefix"),
+		fx.Provide(
+			AsMiddleware(noopMiddleware, 10),
+			AsMiddleware(echo.MiddlewareFunc(noopMiddleware), 20),
+			AsMiddleware(func(_ string) echo.MiddlewareFunc { return noopMiddleware }, -1),
+			AsMiddleware(func() (echo.MiddlewareFunc, error) { return noopMiddleware, nil }, 5),
+		),
+		fx.Populate(&registry),
+	)
+	app.RequireStart().RequireStop()
+	priorities := make([]int, 0, len(registry.Middlewares))
+	for _, mw := range registry.Middlewares {
+		assert.NotNil(t, mw.Func)
+		priorities = append(priorities, mw.Priority)
+	}
+	assert.ElementsMatch(t, []int{10, 20, -1, 5}, priorities)
+
+	// Failing builder
+	err := fx.New(
+		fx.NopLogger,
+		fx.Provide(
+			AsMiddleware(func() (echo.MiddlewareFunc, error) { return nil, errors.New("failed") }, 0),
+		),
+		fx.Populate(&registry),
+	).Err()
+	assert.Error(t, err)
+}
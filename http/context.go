@@ -0,0 +1,20 @@
+package http
+
+import (
+	"context"
+
+	"github.com/bosonicalio/enclave/logging"
+)
+
+// WithRequestID sets the request identifier `id` in `ctx`.
+//
+// The identifier is set as correlation field (see [logging.WithRequestID]), so it is appended to records logged
+// with `ctx`.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return logging.WithRequestID(ctx, id)
+}
+
+// RequestID retrieves the request identifier from `ctx`. Returns an empty string if none was set.
+func RequestID(ctx context.Context) string {
+	return logging.Field(ctx, logging.RequestIDKey)
+}
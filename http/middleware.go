@@ -0,0 +1,38 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+)
+
+// Priorities of the middlewares registered by enclave modules. Middlewares registered with the
+// default priority (zero) run after them.
+const (
+	// PriorityAuthentication is the priority of the authentication middleware.
+	PriorityAuthentication = -200
+	// PriorityAuthorization is the priority of the authorization middleware.
+	PriorityAuthorization = -150
+	// PriorityRateLimit is the priority of the rate limiting middleware.
+	PriorityRateLimit = -100
+)
+
+// Middleware is an [echo.MiddlewareFunc] registered into the HTTP server with an execution priority.
+//
+// Middlewares are applied to every endpoint of the HTTP server in ascending priority order, meaning a middleware
+// with a lower priority wraps (i.e. runs before) middlewares with higher priorities. Middlewares with the same
+// priority keep their registration order.
+type Middleware struct {
+	// Func the middleware routine.
+	Func echo.MiddlewareFunc
+	// Priority the execution priority of the middleware. Lower values run first.
+	Priority int
+}
+
+// MiddlewareController is a [geckhttp.Controller] declaring middlewares only applied to its own
+// versioned endpoints (i.e. those set through [geckhttp.Controller.SetVersionedEndpoints]).
+type MiddlewareController interface {
+	geckhttp.Controller
+	// Middlewares returns the middlewares to apply to the controller versioned endpoints, in execution order.
+	Middlewares() []echo.MiddlewareFunc
+}
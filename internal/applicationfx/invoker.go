@@ -3,18 +3,12 @@ package applicationfx
 import (
 	"log/slog"
 	"runtime"
-
-	"github.com/bosonicalio/geck/application"
-
-	"github.com/bosonicalio/enclave/internal/globallog"
 )
 
-func logAppStart(config application.Application) {
-	globallog.Logger().Info("starting application",
-		slog.String("name", config.Name),
-		slog.String("environment", config.Environment.String()),
-		slog.String("version", config.Version.String()),
-		slog.String("instance_id", config.InstanceID),
+// logAppStart logs the application start. The application metadata is added by the application logger
+// (`app` group).
+func logAppStart(logger *slog.Logger) {
+	logger.Info("starting application",
 		slog.Group("runtime",
 			slog.Int("cpus", runtime.NumCPU()),
 			slog.Group("go",
@@ -21,6 +21,11 @@ var Module = fx.Module("enclave/application",
 	),
 )
 
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
 // -- Factory --
 
 func newApp(cfg config) (application.Application, error) {
@@ -0,0 +1,21 @@
+package authnfx
+
+import "time"
+
+type config struct {
+	Issuer             string        `env:"AUTH_JWT_ISSUER"`
+	Audience           []string      `env:"AUTH_JWT_AUDIENCE"`
+	Algorithms         []string      `env:"AUTH_JWT_ALGORITHMS" envDefault:"RS256,ES256" validate:"required,dive,oneof=HS256 HS384 HS512 RS256 RS384 RS512 PS256 PS384 PS512 ES256 ES384 ES512 EdDSA"`
+	Leeway             time.Duration `env:"AUTH_JWT_LEEWAY" envDefault:"30s" validate:"gte=0"`
+	HMACSecret         string        `env:"AUTH_JWT_HMAC_SECRET" secret:"true"`
+	PublicKeyFile      string        `env:"AUTH_JWT_PUBLIC_KEY_FILE"`
+	PublicKey          string        `env:"AUTH_JWT_PUBLIC_KEY"`
+	SubjectClaim       string        `env:"AUTH_JWT_SUBJECT_CLAIM" envDefault:"sub" validate:"required"`
+	AuthoritiesClaims  []string      `env:"AUTH_JWT_AUTHORITIES_CLAIMS" envDefault:"scope,scp,roles"`
+	JWKSURL            string        `env:"AUTH_JWKS_URL" validate:"omitempty,url"`
+	JWKSCacheTTL       time.Duration `env:"AUTH_JWKS_CACHE_TTL" envDefault:"10m" validate:"gt=0"`
+	JWKSMinRefresh     time.Duration `env:"AUTH_JWKS_MIN_REFRESH_INTERVAL" envDefault:"30s" validate:"gte=0"`
+	JWKSRequestTimeout time.Duration `env:"AUTH_JWKS_REQUEST_TIMEOUT" envDefault:"5s" validate:"gt=0"`
+	RequireAll         bool          `env:"AUTH_REQUIRE_ALL"`
+	SkipPaths          []string      `env:"AUTH_SKIP_PATHS"`
+}
@@ -0,0 +1,280 @@
+package authnfx
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var errKeyNotFound = errors.New("enclave.authn: token signing key not found")
+
+// keySet resolves the key used to verify a token signature.
+type keySet interface {
+	// keyFunc returns the verification key of `token`. It complies with [jwt.Keyfunc].
+	keyFunc(ctx context.Context, token *jwt.Token) (any, error)
+}
+
+// -- Static --
+
+// staticKeySet is a [keySet] using a single key (HMAC secret or PEM-encoded public key) for every token.
+type staticKeySet struct {
+	key any
+}
+
+// compile-time assertion
+var _ keySet = (*staticKeySet)(nil)
+
+func newStaticKeySet(cfg config) (staticKeySet, error) {
+	if cfg.HMACSecret != "" {
+		return staticKeySet{key: []byte(cfg.HMACSecret)}, nil
+	}
+	keyPEM := []byte(cfg.PublicKey)
+	if cfg.PublicKeyFile != "" {
+		var err error
+		keyPEM, err = os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return staticKeySet{}, err
+		}
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(keyPEM); err == nil {
+		return staticKeySet{key: key}, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(keyPEM); err == nil {
+		return staticKeySet{key: key}, nil
+	}
+	key, err := jwt.ParseEdPublicKeyFromPEM(keyPEM)
+	if err != nil {
+		return staticKeySet{}, errors.New("enclave.authn: invalid public key, must be a PEM-encoded RSA, EC or Ed25519 key")
+	}
+	return staticKeySet{key: key}, nil
+}
+
+func (s staticKeySet) keyFunc(_ context.Context, _ *jwt.Token) (any, error) {
+	return s.key, nil
+}
+
+// -- JWKS --
+
+// jwksKeySet is a [keySet] fetching keys from a JSON Web Key Set endpoint.
+//
+// Keys are cached during the configured TTL. Tokens signed with unknown keys trigger a refresh, so key rotations
+// are picked up without waiting for the cache to expire. Refreshes happen at most once per minimum refresh
+// interval and are shared by concurrent requests. If a refresh fails (e.g. the endpoint is down), cached keys are
+// still used.
+type jwksKeySet struct {
+	url           string
+	client        *http.Client
+	ttl           time.Duration
+	minRefresh    time.Duration
+	now           func() time.Time
+	mu            sync.RWMutex
+	keys          map[string]crypto.PublicKey
+	fetchedAt     time.Time
+	lastAttemptAt time.Time
+	lastErr       error
+	// inflight is closed when the ongoing fetch completes, nil if there is none.
+	inflight chan struct{}
+}
+
+// compile-time assertion
+var _ keySet = (*jwksKeySet)(nil)
+
+func newJWKSKeySet(cfg config) *jwksKeySet {
+	return &jwksKeySet{
+		url: cfg.JWKSURL,
+		client: &http.Client{
+			Timeout: cfg.JWKSRequestTimeout,
+		},
+		ttl:        cfg.JWKSCacheTTL,
+		minRefresh: cfg.JWKSMinRefresh,
+		now:        time.Now,
+	}
+}
+
+func (s *jwksKeySet) keyFunc(ctx context.Context, token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, found, expired := s.lookup(kid)
+	if found && !expired {
+		return key, nil
+	}
+	errRefresh := s.refresh(ctx)
+	// cached keys are kept if the refresh fails, so expired ones are still used
+	if key, found, _ = s.lookup(kid); found {
+		return key, nil
+	}
+	if errRefresh != nil {
+		return nil, errRefresh
+	}
+	return nil, errKeyNotFound
+}
+
+func (s *jwksKeySet) lookup(kid string) (key crypto.PublicKey, found bool, expired bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	expired = s.now().Sub(s.fetchedAt) > s.ttl
+	if kid == "" && len(s.keys) == 1 {
+		for _, key = range s.keys {
+			return key, true, expired
+		}
+	}
+	key, found = s.keys[kid]
+	return key, found, expired
+}
+
+// refresh fetches the key set, unless it was attempted less than the minimum refresh interval ago, returning the
+// error of the last attempt.
+//
+// Concurrent calls wait for a single fetch, performed without holding the lock.
+func (s *jwksKeySet) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if done := s.inflight; done != nil {
+		s.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return s.lastErr
+	}
+	now := s.now()
+	if !s.lastAttemptAt.IsZero() && now.Sub(s.lastAttemptAt) < s.minRefresh {
+		defer s.mu.Unlock()
+		return s.lastErr
+	}
+	done := make(chan struct{})
+	s.inflight = done
+	s.lastAttemptAt = now
+	s.mu.Unlock()
+
+	// shared by every waiting request, so it must not be canceled by the first one
+	keys, err := s.fetch(context.WithoutCancel(ctx))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = now
+	}
+	s.lastErr = err
+	s.inflight = nil
+	close(done)
+	return err
+}
+
+type jsonWebKey struct {
+	KeyID     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	Algorithm string `json:"alg"`
+}
+
+func (s *jwksKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("enclave.authn: failed to fetch jwks: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("enclave.authn: failed to fetch jwks, unexpected status %d", res.StatusCode)
+	}
+
+	var body struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("enclave.authn: failed to decode jwks: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(body.Keys))
+	for _, jwk := range body.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, errKey := parseJSONWebKey(jwk)
+		if errKey != nil {
+			// ignore unsupported keys, other keys in the set might still be valid
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+func parseJSONWebKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("enclave.authn: unsupported curve %q", jwk.Curve)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Curve != "Ed25519" {
+			return nil, fmt.Errorf("enclave.authn: unsupported curve %q", jwk.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("enclave.authn: invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("enclave.authn: unsupported key type %q", jwk.KeyType)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
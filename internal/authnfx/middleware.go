@@ -0,0 +1,49 @@
+package authnfx
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/enclave/authn"
+	"github.com/bosonicalio/enclave/logging"
+)
+
+// newMiddleware authenticates requests carrying a bearer token in the `Authorization` header, setting the
+// authenticated principal in the request context (see [authn.PrincipalFromContext]).
+//
+// Requests with invalid tokens are rejected. Requests without token are only rejected if authentication is
+// required for every endpoint; otherwise, routes declare their own requirements (e.g. [authn.RequireAuthenticated]).
+func newMiddleware(cfg config, v verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(cfg.SkipPaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+			scheme, rawToken, found := strings.Cut(authorization, " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || rawToken == "" {
+				if cfg.RequireAll {
+					return authn.ErrUnauthenticated
+				}
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			principal, err := v.verify(ctx, rawToken)
+			if err != nil {
+				logging.FromContext(ctx).DebugContext(ctx, "rejected bearer token",
+					slog.String("error", err.Error()),
+				)
+				return authn.ErrUnauthenticated
+			}
+			ctx = authn.WithPrincipal(ctx, principal)
+			ctx = logging.WithPrincipalID(ctx, principal.ID())
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
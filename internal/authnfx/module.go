@@ -0,0 +1,82 @@
+package authnfx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"go.uber.org/fx"
+
+	enclavehttp "github.com/bosonicalio/enclave/http"
+	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/healthfx"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Module is the `uber/fx` module for HTTP server authentication.
+//
+// It registers a middleware verifying bearer JWTs against a static key (`AUTH_JWT_HMAC_SECRET`,
+// `AUTH_JWT_PUBLIC_KEY_FILE` or `AUTH_JWT_PUBLIC_KEY`) or a JSON Web Key Set endpoint (`AUTH_JWKS_URL`),
+// placing the authenticated [github.com/bosonicalio/enclave/authn.Principal] in the request context.
+//
+// Health endpoints are never authenticated if the health module is present, so probes keep working when
+// `AUTH_REQUIRE_ALL` is set.
+var Module = fx.Module("enclave/authn",
+	fx.Provide(
+		osenv.ParseAs[config],
+		newKeySet,
+		newVerifier,
+		enclavehttp.AsMiddleware(newMiddleware, enclavehttp.PriorityAuthentication),
+	),
+	fx.Decorate(
+		fx.Annotate(
+			skipHealthPaths,
+			fx.ParamTags("", `optional:"true"`), // health monitor is optional
+		),
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// -- Factory --
+
+// skipHealthPaths adds the health endpoints of `monitor` to the paths skipped by authentication.
+func skipHealthPaths(cfg config, monitor *healthfx.Monitor) config {
+	if monitor == nil {
+		return cfg
+	}
+	cfg.SkipPaths = append(slices.Clone(cfg.SkipPaths), monitor.Paths()...)
+	return cfg
+}
+
+func newKeySet(lc fx.Lifecycle, cfg config) (keySet, error) {
+	hasStaticKey := cfg.HMACSecret != "" || cfg.PublicKeyFile != "" || cfg.PublicKey != ""
+	switch {
+	case hasStaticKey && cfg.JWKSURL != "":
+		return nil, errors.New("enclave.authn: static keys and jwks url are mutually exclusive")
+	case hasStaticKey:
+		return newStaticKeySet(cfg)
+	case cfg.JWKSURL != "":
+		keys := newJWKSKeySet(cfg)
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				// keys are fetched lazily if the endpoint is not available yet
+				if err := keys.refresh(ctx); err != nil {
+					globallog.Logger().WarnContext(ctx, "failed to fetch jwks",
+						slog.String("url", cfg.JWKSURL),
+						slog.String("error", err.Error()),
+					)
+				}
+				return nil
+			},
+		})
+		return keys, nil
+	default:
+		return nil, errors.New("enclave.authn: a static key or a jwks url is required " +
+			"(AUTH_JWT_HMAC_SECRET, AUTH_JWT_PUBLIC_KEY_FILE, AUTH_JWT_PUBLIC_KEY or AUTH_JWKS_URL)")
+	}
+}
@@ -0,0 +1,42 @@
+package authnfx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	enclavehttp "github.com/bosonicalio/enclave/http"
+	"github.com/bosonicalio/enclave/internal/healthfx"
+)
+
+func TestModule_SkipHealthPaths(t *testing.T) {
+	t.Setenv("AUTH_JWT_HMAC_SECRET", "secret")
+	t.Setenv("AUTH_JWT_ALGORITHMS", "HS256")
+	t.Setenv("AUTH_REQUIRE_ALL", "true")
+	var deps struct {
+		fx.In
+		Middlewares []enclavehttp.Middleware `group:"http_middlewares"`
+	}
+	fxtest.New(t, healthfx.Module, Module, fx.Populate(&deps))
+	require.Len(t, deps.Middlewares, 1)
+
+	e := echo.New()
+	e.HTTPErrorHandler = geckhttp.NewErrorHandler("json")
+	e.Use(deps.Middlewares[0].Func)
+	handler := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.GET("/readyz", handler)
+	e.GET("/items", handler)
+	for path, code := range map[string]int{"/readyz": http.StatusNoContent, "/items": http.StatusUnauthorized} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, rec.Code, path)
+	}
+}
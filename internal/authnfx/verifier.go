@@ -0,0 +1,85 @@
+package authnfx
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/samber/lo"
+
+	"github.com/bosonicalio/geck/security/identity"
+
+	"github.com/bosonicalio/enclave/authn"
+)
+
+// verifier verifies bearer tokens, building [authn.Principal] instances out of their claims.
+type verifier struct {
+	cfg    config
+	keys   keySet
+	parser *jwt.Parser
+}
+
+func newVerifier(cfg config, keys keySet) verifier {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.Algorithms),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if len(cfg.Audience) > 0 {
+		opts = append(opts, jwt.WithAudience(cfg.Audience...))
+	}
+	return verifier{
+		cfg:    cfg,
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}
+}
+
+// verify verifies `rawToken` signature and claims. Returns the principal the token was issued to.
+func (v verifier) verify(ctx context.Context, rawToken string) (authn.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (any, error) {
+		return v.keys.keyFunc(ctx, token)
+	})
+	if err != nil {
+		return authn.Principal{}, err
+	}
+
+	subject, _ := claims[v.cfg.SubjectClaim].(string)
+	if subject == "" {
+		return authn.Principal{}, errors.New("enclave.authn: token subject claim is missing")
+	}
+	principal := authn.Principal{
+		BasicPrincipal: identity.NewBasicPrincipal(subject, v.newAuthorities(claims)...),
+		Claims:         claims,
+	}
+	principal.Issuer, _ = claims.GetIssuer()
+	principal.Audience, _ = claims.GetAudience()
+	if expiresAt, _ := claims.GetExpirationTime(); expiresAt != nil {
+		principal.ExpiresAt = expiresAt.Time
+	}
+	return principal, nil
+}
+
+// newAuthorities collects authorities from every configured claim, either space-separated strings
+// (e.g. OAuth2 `scope`) or string arrays (e.g. `roles`).
+func (v verifier) newAuthorities(claims jwt.MapClaims) []string {
+	authorities := make([]string, 0)
+	for _, claimName := range v.cfg.AuthoritiesClaims {
+		switch value := claims[claimName].(type) {
+		case string:
+			authorities = append(authorities, strings.Fields(value)...)
+		case []any:
+			for _, item := range value {
+				if authority, ok := item.(string); ok {
+					authorities = append(authorities, authority)
+				}
+			}
+		}
+	}
+	return lo.Uniq(authorities)
+}
@@ -0,0 +1,242 @@
+package authnfx
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	cfg := config{
+		Issuer:            "https://issuer.example.com",
+		Audience:          []string{"enclave"},
+		Algorithms:        []string{"HS256"},
+		HMACSecret:        "secret",
+		SubjectClaim:      "sub",
+		AuthoritiesClaims: []string{"scope", "roles"},
+	}
+	keys, err := newStaticKeySet(cfg)
+	require.NoError(t, err)
+	v := newVerifier(cfg, keys)
+
+	newToken := func(claims jwt.MapClaims) string {
+		token, errSign := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		require.NoError(t, errSign)
+		return token
+	}
+	claims := jwt.MapClaims{
+		"sub":   "user-1",
+		"iss":   "https://issuer.example.com",
+		"aud":   "enclave",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "orders:read orders:write",
+		"roles": []string{"admin", "orders:read"},
+	}
+	principal, err := v.verify(context.Background(), newToken(claims))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", principal.ID())
+	assert.ElementsMatch(t, []string{"orders:read", "orders:write", "admin"}, principal.Authorities())
+	assert.Equal(t, "https://issuer.example.com", principal.Issuer)
+
+	// Wrong audience
+	claims["aud"] = "other"
+	_, err = v.verify(context.Background(), newToken(claims))
+	assert.Error(t, err)
+
+	// Expired
+	claims["aud"] = "enclave"
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = v.verify(context.Background(), newToken(claims))
+	assert.Error(t, err)
+
+	// Disallowed algorithm
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS384, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = v.verify(context.Background(), token)
+	assert.Error(t, err)
+}
+
+func TestJWKSKeySet_KeyFunc(t *testing.T) {
+	keyA, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyB, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var (
+		current = atomic.Pointer[rsa.PrivateKey]{}
+		fetches atomic.Int32
+	)
+	current.Store(keyA)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		key := current.Load()
+		kid := "a"
+		if key == keyB {
+			kid = "b"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	cfg := config{
+		Algorithms:         []string{"RS256"},
+		SubjectClaim:       "sub",
+		JWKSURL:            srv.URL,
+		JWKSCacheTTL:       time.Hour,
+		JWKSRequestTimeout: time.Second,
+	}
+	v := newVerifier(cfg, newJWKSKeySet(cfg))
+	newToken := func(kid string, key *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "user-1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = kid
+		signed, errSign := token.SignedString(key)
+		require.NoError(t, errSign)
+		return signed
+	}
+
+	_, err = v.verify(context.Background(), newToken("a", keyA))
+	require.NoError(t, err)
+	_, err = v.verify(context.Background(), newToken("a", keyA))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Rotated key is fetched on demand
+	current.Store(keyB)
+	_, err = v.verify(context.Background(), newToken("b", keyB))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// Unknown keys fail
+	_, err = v.verify(context.Background(), newToken("c", keyB))
+	assert.Error(t, err)
+}
+
+// newJWKSStandIn starts a JSON Web Key Set endpoint serving `key` as `a`, failing while `down` is true and
+// counting fetches in `fetches`. Responses wait for `hold` to be unlocked.
+func newJWKSStandIn(t *testing.T, key *rsa.PrivateKey, down *atomic.Bool, fetches *atomic.Int32,
+	hold *sync.Mutex) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		hold.Lock()
+		defer hold.Unlock()
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": "a",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestJWKSKeySet_ConcurrentExpiry(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	var (
+		down    atomic.Bool
+		fetches atomic.Int32
+		hold    sync.Mutex
+	)
+	srv := newJWKSStandIn(t, key, &down, &fetches, &hold)
+	keys := newJWKSKeySet(config{
+		JWKSURL:            srv.URL,
+		JWKSCacheTTL:       time.Minute,
+		JWKSMinRefresh:     30 * time.Second,
+		JWKSRequestTimeout: time.Second,
+	})
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+	require.NoError(t, keys.refresh(context.Background()))
+
+	// every request finds the cache expired, a single fetch is performed
+	hold.Lock()
+	now = now.Add(2 * time.Minute)
+	token := &jwt.Token{Header: map[string]any{"kid": "a"}}
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errKey := keys.keyFunc(context.Background(), token)
+			assert.NoError(t, errKey)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	hold.Unlock()
+	wg.Wait()
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestJWKSKeySet_EndpointDown(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	var (
+		down    atomic.Bool
+		fetches atomic.Int32
+		hold    sync.Mutex
+	)
+	srv := newJWKSStandIn(t, key, &down, &fetches, &hold)
+	keys := newJWKSKeySet(config{
+		JWKSURL:            srv.URL,
+		JWKSCacheTTL:       time.Minute,
+		JWKSMinRefresh:     30 * time.Second,
+		JWKSRequestTimeout: time.Second,
+	})
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+	require.NoError(t, keys.refresh(context.Background()))
+
+	// expired keys are still used, and the endpoint is not requested again until the minimum refresh interval
+	down.Store(true)
+	now = now.Add(2 * time.Minute)
+	token := &jwt.Token{Header: map[string]any{"kid": "a"}}
+	for range 10 {
+		got, errKey := keys.keyFunc(context.Background(), token)
+		require.NoError(t, errKey)
+		assert.Equal(t, &key.PublicKey, got)
+	}
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// unknown keys fail with the refresh error
+	_, err = keys.keyFunc(context.Background(), &jwt.Token{Header: map[string]any{"kid": "b"}})
+	assert.ErrorContains(t, err, "unexpected status 503")
+	assert.Equal(t, int32(2), fetches.Load())
+
+	now = now.Add(30 * time.Second)
+	_, err = keys.keyFunc(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), fetches.Load())
+}
@@ -0,0 +1,8 @@
+package authzfx
+
+type config struct {
+	PolicyFile   string   `env:"AUTHZ_POLICY_FILE"`
+	Policy       string   `env:"AUTHZ_POLICY"`
+	RolesClaims  []string `env:"AUTHZ_ROLES_CLAIMS" envDefault:"roles"`
+	ScopesClaims []string `env:"AUTHZ_SCOPES_CLAIMS" envDefault:"scope,scp"`
+}
@@ -0,0 +1,77 @@
+package authzfx
+
+import (
+	"errors"
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+
+	"github.com/bosonicalio/enclave/authz"
+	enclavehttp "github.com/bosonicalio/enclave/http"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Module is the `uber/fx` module for authorization.
+//
+// It loads an [authz.Policy] from a YAML or JSON file (`AUTHZ_POLICY_FILE`) or inline (`AUTHZ_POLICY`), providing
+// an [authz.Authorizer] to be injected into services. Routes declared by the policy are enforced by an HTTP
+// server middleware.
+var Module = fx.Module("enclave/authz",
+	fx.Provide(
+		osenv.ParseAs[config],
+		newPolicy,
+		fx.Annotate(
+			newEngine,
+			fx.As(new(authz.Authorizer)),
+		),
+		enclavehttp.AsMiddleware(newMiddleware, enclavehttp.PriorityAuthorization),
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// -- Factory --
+
+func newPolicy(cfg config) (authz.Policy, error) {
+	data := []byte(cfg.Policy)
+	switch {
+	case cfg.PolicyFile != "" && cfg.Policy != "":
+		return authz.Policy{}, errors.New("enclave.authz: policy file and inline policy are mutually exclusive")
+	case cfg.PolicyFile != "":
+		var err error
+		if data, err = os.ReadFile(cfg.PolicyFile); err != nil {
+			return authz.Policy{}, err
+		}
+	case cfg.Policy == "":
+		return authz.Policy{}, errors.New("enclave.authz: a policy is required (AUTHZ_POLICY_FILE or AUTHZ_POLICY)")
+	}
+	return authz.ParsePolicy(data)
+}
+
+func newEngine(cfg config, policy authz.Policy) (*authz.Engine, error) {
+	return authz.NewEngine(policy,
+		authz.WithRolesClaims(cfg.RolesClaims...),
+		authz.WithScopesClaims(cfg.ScopesClaims...),
+	)
+}
+
+// newMiddleware authorizes requests to the routes declared by `policy`. Requests to other routes are left
+// to per-route requirements (see [authz.Require]).
+func newMiddleware(policy authz.Policy, authorizer authz.Authorizer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route, found := policy.FindRoute(c.Request().Method, c.Path())
+			if !found {
+				return next(c)
+			}
+			if err := authz.AuthorizeRequest(c, authorizer, route.Action, route.Resource); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
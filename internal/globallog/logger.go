@@ -4,23 +4,69 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+	"sync/atomic"
+
+	"github.com/bosonicalio/enclave/internal/loghandler"
+	"github.com/bosonicalio/enclave/internal/osenv"
 )
 
 var (
 	_globalLoggerOnce sync.Once
 	_globalLogger     *slog.Logger
+	_appLogger        atomic.Pointer[slog.Logger]
+	_levelOnce        sync.Once
+	_level            = new(slog.LevelVar)
 )
 
+// Level returns the minimum level of enclave loggers, initialized with the configured one (see
+// [loghandler.Config]).
+//
+// It is shared by the global logger and the application logger, so runtime level changes apply to both.
+func Level() *slog.LevelVar {
+	_levelOnce.Do(func() {
+		if cfg, err := osenv.ParseAs[loghandler.Config](); err == nil {
+			_level.Set(cfg.Level)
+		}
+	})
+	return _level
+}
+
 // Logger returns the global logger instance, initializing it if necessary.
 //
 // This logger must be used for all logging operations within enclave internal operations. It is not intended
 // for use in external applications or libraries.
+//
+// Once set (see [SetLogger]), the application logger is returned, so enclave records share its format and
+// attributes. Before that, a logger configured with the same environment variables (see [loghandler.Config])
+// is used.
 func Logger() *slog.Logger {
+	if logger := _appLogger.Load(); logger != nil {
+		return logger
+	}
 	_globalLoggerOnce.Do(func() {
-		// Initialize the global logger with default options
-		_globalLogger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			AddSource: true,
-		}))
+		cfg, err := osenv.ParseAs[loghandler.Config]()
+		var handler slog.Handler
+		if err == nil {
+			handler, err = loghandler.New(cfg, Level())
+		}
+		if err != nil {
+			// Initialize the global logger with default options
+			_globalLogger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+				AddSource: true,
+				Level:     Level(),
+			}))
+			_globalLogger.Warn("invalid logging configuration, using defaults",
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		_globalLogger = slog.New(handler)
 	})
 	return _globalLogger
 }
+
+// SetLogger routes enclave internal logging through `logger`, typically the application logger. A nil `logger`
+// restores the default one.
+func SetLogger(logger *slog.Logger) {
+	_appLogger.Store(logger)
+}
@@ -0,0 +1,9 @@
+package healthfx
+
+import "time"
+
+type config struct {
+	LivenessPath  string        `env:"HEALTH_LIVENESS_PATH" envDefault:"/healthz" validate:"required,startswith=/"`
+	ReadinessPath string        `env:"HEALTH_READINESS_PATH" envDefault:"/readyz" validate:"required,startswith=/"`
+	CheckTimeout  time.Duration `env:"HEALTH_CHECK_TIMEOUT" envDefault:"5s" validate:"gt=0"`
+}
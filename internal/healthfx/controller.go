@@ -0,0 +1,45 @@
+package healthfx
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/health"
+)
+
+type controller struct {
+	cfg     config
+	monitor *Monitor
+}
+
+// compile-time assertion
+var _ geckhttp.Controller = (*controller)(nil)
+
+func newController(cfg config, monitor *Monitor) controller {
+	return controller{
+		cfg:     cfg,
+		monitor: monitor,
+	}
+}
+
+func (c controller) SetEndpoints(e *echo.Echo) {
+	e.GET(c.cfg.LivenessPath, c.getLiveness)
+	e.GET(c.cfg.ReadinessPath, c.getReadiness)
+}
+
+func (c controller) SetVersionedEndpoints(_ *echo.Group) {}
+
+func (c controller) getLiveness(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, c.monitor.Liveness())
+}
+
+func (c controller) getReadiness(ctx echo.Context) error {
+	report := c.monitor.Readiness(ctx.Request().Context())
+	if report.Status == health.StatusDown {
+		return ctx.JSON(http.StatusServiceUnavailable, report)
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
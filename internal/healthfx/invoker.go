@@ -0,0 +1,20 @@
+package healthfx
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+)
+
+func trackReadiness(lc fx.Lifecycle, monitor *Monitor) {
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			monitor.SetReady(true)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			monitor.SetReady(false)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package healthfx
+
+import (
+	"go.uber.org/fx"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Module is the `uber/fx` module for application health reporting.
+//
+// It aggregates every [github.com/bosonicalio/enclave/health.Checker] registered in the `health_checkers`
+// group and exposes liveness (`HEALTH_LIVENESS_PATH`, defaults to `/healthz`) and readiness
+// (`HEALTH_READINESS_PATH`, defaults to `/readyz`) endpoints through the HTTP server module, if present.
+var Module = fx.Module("enclave/health",
+	fx.Provide(
+		osenv.ParseAs[config],
+		fx.Annotate(
+			newMonitor,
+			fx.ParamTags("", `group:"health_checkers"`),
+		),
+		fx.Annotate(
+			newController,
+			fx.As(new(geckhttp.Controller)),
+			fx.ResultTags(`group:"http_controllers"`),
+		),
+	),
+	fx.Invoke(
+		trackReadiness,
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
@@ -0,0 +1,117 @@
+package healthfx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bosonicalio/enclave/health"
+	"github.com/bosonicalio/enclave/logging"
+)
+
+// Monitor aggregates every registered [health.Checker] and keeps track of the readiness state of the
+// application.
+//
+// Readiness is flipped by the application lifecycle (e.g. marked as not ready while the HTTP server
+// is draining connections), while liveness only reflects the process is running.
+type Monitor struct {
+	cfg      config
+	checkers []health.Checker
+	ready    atomic.Bool
+}
+
+// newMonitor allocates a [Monitor]. Fails if several checkers share the same name, as their results would
+// overwrite each other.
+func newMonitor(cfg config, checkers []health.Checker) (*Monitor, error) {
+	names := make(map[string]struct{}, len(checkers))
+	for _, checker := range checkers {
+		if _, ok := names[checker.Name()]; ok {
+			return nil, fmt.Errorf("enclave.health: duplicate health checker %q", checker.Name())
+		}
+		names[checker.Name()] = struct{}{}
+	}
+	return &Monitor{
+		cfg:      cfg,
+		checkers: checkers,
+	}, nil
+}
+
+// Paths returns the paths of the liveness and readiness endpoints.
+func (m *Monitor) Paths() []string {
+	return []string{m.cfg.LivenessPath, m.cfg.ReadinessPath}
+}
+
+// SetReady sets the readiness state of the application.
+func (m *Monitor) SetReady(ready bool) {
+	m.ready.Store(ready)
+}
+
+// IsReady indicates whether the application is accepting traffic.
+func (m *Monitor) IsReady() bool {
+	return m.ready.Load()
+}
+
+// Liveness returns the liveness report of the application.
+func (m *Monitor) Liveness() health.Report {
+	return health.Report{
+		Status: health.StatusUp,
+	}
+}
+
+// Readiness executes every registered [health.Checker] concurrently and returns the aggregated report.
+//
+// The report status is [health.StatusDown] if the application was marked as not ready or any of the
+// checks failed. Check errors are logged only, as they might disclose internal details (e.g. hostnames) through
+// unauthenticated endpoints.
+func (m *Monitor) Readiness(ctx context.Context) health.Report {
+	report := health.Report{
+		Status: health.StatusUp,
+		Checks: make(map[string]health.CheckResult, len(m.checkers)),
+	}
+	if !m.IsReady() {
+		report.Status = health.StatusDown
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.CheckTimeout)
+	defer cancel()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, checker := range m.checkers {
+		wg.Add(1)
+		go func(checker health.Checker) {
+			defer wg.Done()
+			result := runCheck(ctx, checker)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[checker.Name()] = result
+			if result.Status == health.StatusDown {
+				report.Status = health.StatusDown
+			}
+		}(checker)
+	}
+	wg.Wait()
+	return report
+}
+
+func runCheck(ctx context.Context, checker health.Checker) health.CheckResult {
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := health.CheckResult{
+		Status: health.StatusUp,
+		Took:   time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = health.StatusDown
+		logging.FromContext(ctx).WarnContext(ctx, "health check failed",
+			slog.String("check", checker.Name()),
+			slog.String("error", err.Error()),
+		)
+	}
+	return result
+}
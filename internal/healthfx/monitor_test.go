@@ -0,0 +1,46 @@
+package healthfx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bosonicalio/enclave/health"
+)
+
+func TestMonitor_Readiness(t *testing.T) {
+	okChecker := health.NewChecker("ok", func(_ context.Context) error { return nil })
+	failChecker := health.NewChecker("fail", func(_ context.Context) error { return errors.New("unreachable") })
+
+	// Not ready
+	// Duplicate checkers
+	_, err := newMonitor(config{CheckTimeout: time.Second}, []health.Checker{okChecker, okChecker})
+	assert.Error(t, err)
+
+	monitor, err := newMonitor(config{CheckTimeout: time.Second}, []health.Checker{okChecker})
+	require.NoError(t, err)
+	report := monitor.Readiness(context.Background())
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, health.StatusUp, report.Checks["ok"].Status)
+
+	// Ready and all checks passing
+	monitor.SetReady(true)
+	report = monitor.Readiness(context.Background())
+	assert.Equal(t, health.StatusUp, report.Status)
+
+	// Ready with a failing check
+	monitor, err = newMonitor(config{CheckTimeout: time.Second}, []health.Checker{okChecker, failChecker})
+	require.NoError(t, err)
+	monitor.SetReady(true)
+	report = monitor.Readiness(context.Background())
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, health.StatusUp, report.Checks["ok"].Status)
+	assert.Equal(t, health.StatusDown, report.Checks["fail"].Status)
+
+	// Liveness does not depend on checks
+	assert.Equal(t, health.StatusUp, monitor.Liveness().Status)
+}
@@ -0,0 +1,20 @@
+package loghandler
+
+import "log/slog"
+
+// Config is the configuration of the handlers built by [New].
+type Config struct {
+	Format    string     `env:"LOG_FORMAT" envDefault:"json" validate:"oneof=json text pretty"`
+	Level     slog.Level `env:"LOG_LEVEL" envDefault:"DEBUG"`
+	AddSource bool       `env:"LOG_ADD_SOURCE" envDefault:"true"`
+
+	Output         string `env:"LOG_OUTPUT" envDefault:"stdout" validate:"oneof=stdout stderr file"`
+	FilePath       string `env:"LOG_FILE_PATH" validate:"required_if=Output file"`
+	FileMaxSize    int    `env:"LOG_FILE_MAX_SIZE_MB" envDefault:"100" validate:"gt=0"`
+	FileMaxBackups int    `env:"LOG_FILE_MAX_BACKUPS" envDefault:"5" validate:"gte=0"`
+	FileMaxAge     int    `env:"LOG_FILE_MAX_AGE_DAYS" validate:"gte=0"`
+	FileCompress   bool   `env:"LOG_FILE_COMPRESS"`
+
+	RedactKeys          []string `env:"LOG_REDACT_KEYS" envDefault:"password,passwd,secret,client_secret,token,access_token,refresh_token,id_token,api_key,apikey,authorization,cookie,set_cookie,private_key,connection_string"`
+	RedactValuePatterns []string `env:"LOG_REDACT_VALUE_PATTERNS"`
+}
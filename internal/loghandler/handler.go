@@ -0,0 +1,38 @@
+// Package loghandler builds the [slog.Handler] instances used by enclave loggers, configured through
+// environment variables (see [Config]).
+package loghandler
+
+import (
+	"log/slog"
+
+	"github.com/bosonicalio/enclave/logging"
+)
+
+// New allocates a [slog.Handler] configured with `cfg`, enabled for records of `level` or above. Pass a
+// [slog.LevelVar] to change the minimum level at runtime.
+//
+// Records are written in JSON, logfmt-like text or a colored human-readable format (`pretty`, intended for local
+// development) to the standard output, standard error or a file rotated by size. Attributes holding secrets are
+// redacted. Handlers append correlation fields from record contexts (see [logging.ContextHandler]).
+func New(cfg Config, level slog.Leveler) (slog.Handler, error) {
+	replaceAttr, err := newRedactor(cfg.RedactKeys, cfg.RedactValuePatterns)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{
+		AddSource:   cfg.AddSource,
+		Level:       level,
+		ReplaceAttr: replaceAttr,
+	}
+	out := newOutput(cfg)
+	var handler slog.Handler
+	switch cfg.Format {
+	case "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "pretty":
+		handler = newPrettyHandler(out, opts)
+	default:
+		handler = slog.NewJSONHandler(out, opts)
+	}
+	return logging.NewContextHandler(handler), nil
+}
@@ -0,0 +1,89 @@
+package loghandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{RedactValuePatterns: []string{"("}}, slog.LevelInfo)
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	handler, err := New(Config{
+		Format:              "json",
+		Level:               slog.LevelInfo,
+		Output:              "file",
+		FilePath:            path,
+		FileMaxSize:         1,
+		RedactKeys:          []string{"password", "api_key"},
+		RedactValuePatterns: []string{`(?i)bearer\s+\S+`},
+	}, slog.LevelInfo)
+	require.NoError(t, err)
+	logger := slog.New(handler)
+	logger.Debug("skipped")
+	logger.Info("connecting",
+		slog.String("Password", "hunter2"),
+		slog.Group("client", slog.String("API-Key", "abc")),
+		slog.String("header", "Bearer eyJhbGciOi.payload.sig"),
+		slog.String("user", "alice"),
+		slog.Any("error", errors.New("dial postgres: bearer abc123 rejected")),
+		slog.Group("request", slog.Any("auth", tokenValuer("Bearer xyz"))),
+		slog.Int("attempt", 2),
+	)
+	require.NoError(t, Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, RedactedValue, record["Password"])
+	assert.Equal(t, map[string]any{"API-Key": RedactedValue}, record["client"])
+	assert.Equal(t, RedactedValue, record["header"])
+	assert.Equal(t, "alice", record["user"])
+	assert.Equal(t, "dial postgres: [REDACTED] rejected", record["error"])
+	assert.Equal(t, map[string]any{"auth": RedactedValue}, record["request"])
+	assert.Equal(t, float64(2), record["attempt"])
+}
+
+type tokenValuer string
+
+func (v tokenValuer) LogValue() slog.Value {
+	return slog.StringValue(string(v))
+}
+
+func TestPrettyHandler(t *testing.T) {
+	var buf bytes.Buffer
+	replaceAttr, err := newRedactor([]string{"token"}, nil)
+	require.NoError(t, err)
+	logger := slog.New(newPrettyHandler(&buf, &slog.HandlerOptions{
+		Level:       slog.LevelDebug,
+		ReplaceAttr: replaceAttr,
+	}))
+	logger.With(slog.String("request_id", "42")).WithGroup("http").Debug("handled request",
+		slog.Int("status", 200),
+		slog.String("token", "secret"),
+		slog.String("path", "/orders list"),
+	)
+
+	line := buf.String()
+	assert.Contains(t, line, "DBG")
+	assert.Contains(t, line, "handled request")
+	assert.Contains(t, line, "request_id=")
+	assert.Contains(t, line, "http.status=")
+	assert.Contains(t, line, `"/orders list"`)
+	assert.Contains(t, line, RedactedValue)
+	assert.NotContains(t, line, "secret")
+	assert.True(t, strings.HasSuffix(line, "\n"))
+}
@@ -0,0 +1,43 @@
+package loghandler
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// _fileWriters keeps a single writer per log file, so handlers built from the same configuration share
+// rotation state.
+var _fileWriters sync.Map
+
+func newOutput(cfg Config) io.Writer {
+	switch cfg.Output {
+	case "stderr":
+		return os.Stderr
+	case "file":
+		writer, _ := _fileWriters.LoadOrStore(cfg.FilePath, &lumberjack.Logger{
+			Filename:   cfg.FilePath,
+			MaxSize:    cfg.FileMaxSize,
+			MaxBackups: cfg.FileMaxBackups,
+			MaxAge:     cfg.FileMaxAge,
+			Compress:   cfg.FileCompress,
+		})
+		return writer.(io.Writer)
+	default:
+		return os.Stdout
+	}
+}
+
+// Close closes the log files written by handlers. Files are reopened if records are written afterward (e.g. by
+// another application of the process), so handlers remain usable.
+func Close() error {
+	var errs []error
+	_fileWriters.Range(func(_, writer any) bool {
+		errs = append(errs, writer.(*lumberjack.Logger).Close())
+		return true
+	})
+	return errors.Join(errs...)
+}
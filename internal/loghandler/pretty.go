@@ -0,0 +1,148 @@
+package loghandler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	_ansiReset  = "\033[0m"
+	_ansiDim    = "\033[2m"
+	_ansiRed    = "\033[31m"
+	_ansiYellow = "\033[33m"
+	_ansiBlue   = "\033[34m"
+	_ansiPurple = "\033[35m"
+)
+
+// prettyHandler is a [slog.Handler] writing colored, human-readable records
+// (e.g. `15:04:05.000 INF starting application name=orders`), intended for local development.
+type prettyHandler struct {
+	opts   slog.HandlerOptions
+	mu     *sync.Mutex
+	out    io.Writer
+	attrs  []byte
+	groups []string
+}
+
+// compile-time assertion
+var _ slog.Handler = (*prettyHandler)(nil)
+
+func newPrettyHandler(out io.Writer, opts *slog.HandlerOptions) *prettyHandler {
+	return &prettyHandler{
+		opts: *opts,
+		mu:   &sync.Mutex{},
+		out:  out,
+	}
+}
+
+func (h *prettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *prettyHandler) Handle(_ context.Context, record slog.Record) error {
+	buf := make([]byte, 0, 256)
+	if !record.Time.IsZero() {
+		buf = append(buf, _ansiDim...)
+		buf = record.Time.AppendFormat(buf, time.TimeOnly+".000")
+		buf = append(buf, _ansiReset...)
+		buf = append(buf, ' ')
+	}
+	buf = appendLevel(buf, record.Level)
+	buf = append(buf, ' ')
+	buf = append(buf, record.Message...)
+	if h.opts.AddSource && record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		buf = h.appendAttr(buf, nil, slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", frame.File, frame.Line)))
+	}
+	buf = append(buf, h.attrs...)
+	record.Attrs(func(a slog.Attr) bool {
+		buf = h.appendAttr(buf, h.groups, a)
+		return true
+	})
+	buf = append(buf, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.out.Write(buf)
+	return err
+}
+
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]byte(nil), h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = h.appendAttr(clone.attrs, h.groups, a)
+	}
+	return &clone
+}
+
+func (h *prettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string(nil), h.groups...), name)
+	return &clone
+}
+
+func (h *prettyHandler) appendAttr(buf []byte, groups []string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(append([]string(nil), groups...), a.Key)
+		}
+		for _, member := range a.Value.Group() {
+			buf = h.appendAttr(buf, groups, member)
+		}
+		return buf
+	}
+
+	buf = append(buf, ' ')
+	buf = append(buf, _ansiDim...)
+	for _, group := range groups {
+		buf = append(buf, group...)
+		buf = append(buf, '.')
+	}
+	buf = append(buf, a.Key...)
+	buf = append(buf, '=')
+	buf = append(buf, _ansiReset...)
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	if a.Key == "error" || a.Key == "err" {
+		return append(buf, _ansiRed+value+_ansiReset...)
+	}
+	return append(buf, value...)
+}
+
+func appendLevel(buf []byte, level slog.Level) []byte {
+	switch {
+	case level >= slog.LevelError:
+		return append(buf, _ansiRed+"ERR"+_ansiReset...)
+	case level >= slog.LevelWarn:
+		return append(buf, _ansiYellow+"WRN"+_ansiReset...)
+	case level >= slog.LevelInfo:
+		return append(buf, _ansiBlue+"INF"+_ansiReset...)
+	default:
+		return append(buf, _ansiPurple+"DBG"+_ansiReset...)
+	}
+}
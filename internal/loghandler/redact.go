@@ -0,0 +1,84 @@
+package loghandler
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted attributes.
+const RedactedValue = "[REDACTED]"
+
+// newRedactor returns a [slog.HandlerOptions.ReplaceAttr] routine replacing the values of attributes whose key
+// is in `keys` (case-insensitive, dashes are considered underscores), and the parts of values matching `patterns`.
+//
+// Values are resolved first (see [slog.LogValuer]) and groups are redacted recursively. Values other than strings
+// (e.g. errors) are matched against `patterns` through their text representation, and replaced by it once
+// redacted.
+func newRedactor(keys []string, patterns []string) (func(groups []string, a slog.Attr) slog.Attr, error) {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[normalizeKey(key)] = struct{}{}
+	}
+	exprs := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("enclave.logging: invalid redact pattern %q: %w", pattern, err)
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(keySet) == 0 && len(exprs) == 0 {
+		return nil, nil
+	}
+
+	var redact func(a slog.Attr) slog.Attr
+	redact = func(a slog.Attr) slog.Attr {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			attrs := a.Value.Group()
+			redacted := make([]slog.Attr, 0, len(attrs))
+			for _, attr := range attrs {
+				redacted = append(redacted, redact(attr))
+			}
+			return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+		}
+		if _, ok := keySet[normalizeKey(a.Key)]; ok {
+			return slog.String(a.Key, RedactedValue)
+		}
+		if len(exprs) == 0 {
+			return a
+		}
+		var value string
+		switch a.Value.Kind() {
+		case slog.KindString:
+			value = a.Value.String()
+		case slog.KindAny:
+			// errors and other values (e.g. URLs with credentials) are redacted as their text representation
+			if err, ok := a.Value.Any().(error); ok {
+				value = err.Error()
+			} else {
+				value = fmt.Sprint(a.Value.Any())
+			}
+		default:
+			return a
+		}
+		redacted := value
+		for _, expr := range exprs {
+			redacted = expr.ReplaceAllString(redacted, RedactedValue)
+		}
+		if a.Value.Kind() != slog.KindString && redacted == value {
+			// kept as is, so the handler encodes it as usual
+			return a
+		}
+		return slog.String(a.Key, redacted)
+	}
+	return func(_ []string, a slog.Attr) slog.Attr {
+		return redact(a)
+	}, nil
+}
+
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
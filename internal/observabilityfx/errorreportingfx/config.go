@@ -0,0 +1,9 @@
+package errorreportingfx
+
+import "time"
+
+type config struct {
+	DSN          string        `env:"ERROR_REPORTING_DSN" validate:"required,url" secret:"true"`
+	SampleRate   float64       `env:"ERROR_REPORTING_SAMPLE_RATE" envDefault:"1" validate:"gt=0,lte=1"`
+	FlushTimeout time.Duration `env:"ERROR_REPORTING_FLUSH_TIMEOUT" envDefault:"2s" validate:"gt=0"`
+}
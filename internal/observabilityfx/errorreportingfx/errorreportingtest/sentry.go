@@ -0,0 +1,46 @@
+// Package errorreportingtest contains test helpers of the error reporting module.
+package errorreportingtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SentryStandIn is a local HTTP stand-in of a Sentry-compatible service, collecting received envelopes.
+type SentryStandIn struct {
+	server    *httptest.Server
+	mu        sync.Mutex
+	envelopes []string
+}
+
+// NewSentryStandIn starts a [SentryStandIn], closed once `tb` finishes.
+func NewSentryStandIn(tb testing.TB) *SentryStandIn {
+	standIn := &SentryStandIn{}
+	standIn.server = httptest.NewServer(http.HandlerFunc(standIn.collect))
+	tb.Cleanup(standIn.server.Close)
+	return standIn
+}
+
+func (s *SentryStandIn) collect(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.envelopes = append(s.envelopes, string(body))
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+// DSN returns the DSN of the stand-in, to be set as `ERROR_REPORTING_DSN`.
+func (s *SentryStandIn) DSN() string {
+	return strings.Replace(s.server.URL, "http://", "http://public@", 1) + "/1"
+}
+
+// Received returns the envelopes received so far.
+func (s *SentryStandIn) Received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.envelopes...)
+}
@@ -0,0 +1,54 @@
+package errorreportingfx
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/fx/fxevent"
+)
+
+// eventLogger is a [fxevent.Logger] reporting failed lifecycle hooks.
+type eventLogger struct {
+	next     fxevent.Logger
+	reporter *Reporter
+}
+
+// compile-time assertion
+var _ fxevent.Logger = (*eventLogger)(nil)
+
+// NewEventLogger returns a [go.uber.org/fx.WithLogger] constructor of a [fxevent.Logger] reporting failed
+// lifecycle hooks with [Reporter], then forwarding every event to `next`.
+func NewEventLogger(next fxevent.Logger) func(reporter *Reporter) fxevent.Logger {
+	return func(reporter *Reporter) fxevent.Logger {
+		return eventLogger{
+			next:     next,
+			reporter: reporter,
+		}
+	}
+}
+
+func (l eventLogger) LogEvent(event fxevent.Event) {
+	switch e := event.(type) {
+	case *fxevent.OnStartExecuted:
+		l.reportHook(e.Err, "OnStart", e.FunctionName, e.CallerName)
+	case *fxevent.OnStopExecuted:
+		l.reportHook(e.Err, "OnStop", e.FunctionName, e.CallerName)
+	}
+	l.next.LogEvent(event)
+}
+
+func (l eventLogger) reportHook(err error, hook, functionName, callerName string) {
+	if err == nil {
+		return
+	}
+	l.reporter.Report(context.Background(), fmt.Errorf("%s hook %s failed: %w", hook, functionName, err),
+		WithFatalLevel(),
+		WithTags(map[string]string{
+			"fx.hook":     hook,
+			"fx.function": functionName,
+			"fx.caller":   callerName,
+		}),
+	)
+	// the application is about to exit
+	l.reporter.Flush()
+}
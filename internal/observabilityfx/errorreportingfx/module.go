@@ -0,0 +1,35 @@
+package errorreportingfx
+
+import (
+	"go.uber.org/fx"
+
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Module is the `uber/fx` module for error reporting to a Sentry-compatible service (`ERROR_REPORTING_DSN`).
+//
+// It provides a [Reporter] used by the HTTP server module to report panics and 5xx responses. Failed lifecycle
+// hooks are reported if the application event logger is built with [NewEventLogger]. Pending reports are
+// flushed on shutdown.
+var Module = fx.Module("enclave/observability/error_reporting",
+	fx.Provide(
+		osenv.ParseAs[config],
+		newReporter,
+	),
+	fx.Invoke(
+		flushOnStop,
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// -- Invoker --
+
+func flushOnStop(lc fx.Lifecycle, reporter *Reporter) {
+	lc.Append(fx.StopHook(func() {
+		reporter.Flush()
+	}))
+}
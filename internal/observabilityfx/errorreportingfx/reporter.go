@@ -0,0 +1,93 @@
+package errorreportingfx
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/getsentry/sentry-go"
+
+	"github.com/bosonicalio/enclave/logging"
+)
+
+// Reporter ships errors to a Sentry-compatible service, along with their stack traces, the application metadata
+// and the correlation fields of their context (see [logging.WithFields]).
+type Reporter struct {
+	client       *sentry.Client
+	flushTimeout time.Duration
+}
+
+func newReporter(cfg config, app application.Application) (*Reporter, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:              cfg.DSN,
+		SampleRate:       cfg.SampleRate,
+		AttachStacktrace: true,
+		Environment:      app.Environment.String(),
+		Release:          app.Name + "@" + app.Version.String(),
+		ServerName:       app.InstanceID,
+		Tags: map[string]string{
+			"app.name":        app.Name,
+			"app.instance_id": app.InstanceID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Reporter{
+		client:       client,
+		flushTimeout: cfg.FlushTimeout,
+	}, nil
+}
+
+// ReportOption configures a reported error.
+type ReportOption func(*sentry.Scope)
+
+// WithRequest attaches the HTTP request that failed. Sensitive headers (e.g. `Authorization`, `Cookie`) are
+// not sent.
+func WithRequest(req *http.Request) ReportOption {
+	return func(scope *sentry.Scope) {
+		scope.SetRequest(req)
+	}
+}
+
+// WithTags attaches `tags`.
+func WithTags(tags map[string]string) ReportOption {
+	return func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+	}
+}
+
+// WithFatalLevel reports the error as fatal (e.g. panics).
+func WithFatalLevel() ReportOption {
+	return func(scope *sentry.Scope) {
+		scope.SetLevel(sentry.LevelFatal)
+	}
+}
+
+// Report reports `err` asynchronously.
+func (r *Reporter) Report(ctx context.Context, err error, opts ...ReportOption) {
+	scope := sentry.NewScope()
+	for _, field := range logging.Fields(ctx) {
+		switch field.Key {
+		case logging.UserIDKey:
+			scope.SetUser(sentry.User{ID: field.Value.String()})
+		case logging.PrincipalIDKey:
+			// user identifiers set by the application take precedence
+			if logging.Field(ctx, logging.UserIDKey) == "" {
+				scope.SetUser(sentry.User{ID: field.Value.String()})
+			}
+		default:
+			scope.SetTag(field.Key, field.Value.String())
+		}
+	}
+	for _, opt := range opts {
+		opt(scope)
+	}
+	r.client.CaptureException(err, &sentry.EventHint{Context: ctx, OriginalException: err}, scope)
+}
+
+// Flush waits until every reported error is sent, up to the configured timeout.
+func (r *Reporter) Flush() bool {
+	return r.client.Flush(r.flushTimeout)
+}
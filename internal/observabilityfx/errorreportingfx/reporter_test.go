@@ -0,0 +1,81 @@
+package errorreportingfx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/bosonicalio/geck/environment"
+	"github.com/bosonicalio/geck/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/bosonicalio/enclave/internal/observabilityfx/errorreportingfx/errorreportingtest"
+	"github.com/bosonicalio/enclave/logging"
+)
+
+func newTestReporter(t *testing.T) (*Reporter, *errorreportingtest.SentryStandIn) {
+	standIn := errorreportingtest.NewSentryStandIn(t)
+	t.Setenv("ERROR_REPORTING_DSN", standIn.DSN())
+	var reporter *Reporter
+	app := fxtest.New(t,
+		fx.Supply(application.Application{
+			Name:        "orders",
+			Version:     version.MustParse("v1.2.3"),
+			Environment: environment.Production,
+			InstanceID:  "instance-1",
+		}),
+		Module,
+		fx.Populate(&reporter),
+	)
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+	return reporter, standIn
+}
+
+func TestReporter_Report(t *testing.T) {
+	reporter, standIn := newTestReporter(t)
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	ctx = logging.WithUserID(ctx, "user-1")
+	reporter.Report(ctx, errors.New("boom"), WithTags(map[string]string{"http.status": "500"}))
+	require.True(t, reporter.Flush())
+
+	envelopes := standIn.Received()
+	require.Len(t, envelopes, 1)
+	event := envelopes[0]
+	assert.Contains(t, event, `"boom"`)
+	assert.Contains(t, event, `"release":"orders@v1.2.3"`)
+	assert.Contains(t, event, `"environment":"`+environment.Production.String()+`"`)
+	assert.Contains(t, event, `"server_name":"instance-1"`)
+	assert.Contains(t, event, `"request_id":"req-1"`)
+	assert.Contains(t, event, `"http.status":"500"`)
+	assert.Contains(t, event, `"id":"user-1"`)
+	assert.Contains(t, event, `"stacktrace"`)
+}
+
+func TestEventLogger(t *testing.T) {
+	reporter, standIn := newTestReporter(t)
+	next := &bytes.Buffer{}
+	logger := NewEventLogger(&fxevent.ConsoleLogger{W: next})(reporter)
+
+	logger.LogEvent(&fxevent.OnStartExecuted{FunctionName: "startServer", CallerName: "main"})
+	assert.Empty(t, standIn.Received())
+
+	logger.LogEvent(&fxevent.OnStartExecuted{
+		FunctionName: "startServer",
+		CallerName:   "main",
+		Err:          errors.New("address already in use"),
+	})
+	envelopes := standIn.Received()
+	require.Len(t, envelopes, 1)
+	assert.Contains(t, envelopes[0], "OnStart hook startServer failed: address already in use")
+	assert.Contains(t, envelopes[0], `"fx.hook":"OnStart"`)
+	assert.Contains(t, envelopes[0], `"level":"fatal"`)
+	assert.Contains(t, next.String(), "address already in use")
+}
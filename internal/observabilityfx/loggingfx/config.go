@@ -0,0 +1,9 @@
+package loggingfx
+
+import "time"
+
+type levelConfig struct {
+	RevertAfter   time.Duration `env:"LOG_LEVEL_REVERT_AFTER" validate:"gte=0"`
+	EnableSignals bool          `env:"LOG_LEVEL_ENABLE_SIGNALS" envDefault:"true"`
+	AdminPath     string        `env:"LOG_LEVEL_ADMIN_PATH" envDefault:"/admin/log-level" validate:"required,startswith=/"`
+}
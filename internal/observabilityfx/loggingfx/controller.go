@@ -0,0 +1,90 @@
+package loggingfx
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/geck/syserr"
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
+)
+
+type controller struct {
+	cfg   levelConfig
+	level *levelController
+	admin *adminfx.Server
+}
+
+// compile-time assertions
+var (
+	_ geckhttp.Controller = (*controller)(nil)
+	_ adminfx.Controller  = (*controller)(nil)
+)
+
+func newController(cfg levelConfig, level *levelController, admin *adminfx.Server) controller {
+	return controller{
+		cfg:   cfg,
+		level: level,
+		admin: admin,
+	}
+}
+
+func (c controller) SetEndpoints(e *echo.Echo) {
+	if c.admin.Enabled() {
+		// served by the admin server
+		return
+	}
+	c.setLevelEndpoints(e.Group("", c.admin.TokenMiddleware()))
+}
+
+func (c controller) SetVersionedEndpoints(_ *echo.Group) {}
+
+func (c controller) SetAdminEndpoints(_, protected *echo.Group) {
+	c.setLevelEndpoints(protected)
+}
+
+func (c controller) setLevelEndpoints(g *echo.Group) {
+	g.GET(c.cfg.AdminPath, c.getLevel)
+	g.PUT(c.cfg.AdminPath, c.putLevel)
+	g.DELETE(c.cfg.AdminPath, c.deleteLevel)
+}
+
+func (c controller) getLevel(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, c.level.state())
+}
+
+type putLevelRequest struct {
+	Level       string `json:"level"`
+	RevertAfter string `json:"revert_after"`
+}
+
+func (c controller) putLevel(ctx echo.Context) error {
+	var req putLevelRequest
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+		return syserr.New(syserr.InvalidArgument, "invalid log level",
+			syserr.WithInternalCode("INVALID_LOG_LEVEL"),
+		)
+	}
+	revertAfter := c.cfg.RevertAfter
+	if req.RevertAfter != "" {
+		var err error
+		if revertAfter, err = time.ParseDuration(req.RevertAfter); err != nil || revertAfter < 0 {
+			return syserr.New(syserr.InvalidArgument, "invalid revert duration",
+				syserr.WithInternalCode("INVALID_REVERT_DURATION"),
+			)
+		}
+	}
+	return ctx.JSON(http.StatusOK, c.level.set(level, revertAfter))
+}
+
+func (c controller) deleteLevel(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, c.level.reset())
+}
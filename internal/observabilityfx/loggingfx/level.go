@@ -0,0 +1,142 @@
+package loggingfx
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/loghandler"
+)
+
+// levelState is the current state of a [levelController].
+type levelState struct {
+	Level        string     `json:"level"`
+	DefaultLevel string     `json:"default_level"`
+	RevertAt     *time.Time `json:"revert_at,omitempty"`
+}
+
+// levelController changes the minimum level of enclave loggers at runtime, optionally reverting it to the
+// configured level after a timeout.
+type levelController struct {
+	mu           sync.Mutex
+	level        *slog.LevelVar
+	defaultLevel slog.Level
+	revertTimer  *time.Timer
+	revertAt     time.Time
+}
+
+// newLevel returns the minimum level of the application logger, shared with enclave internal logger (see
+// [globallog.Level]) and set to the configured one.
+func newLevel(cfg loghandler.Config) *slog.LevelVar {
+	level := globallog.Level()
+	level.Set(cfg.Level)
+	return level
+}
+
+func newLevelController(cfg loghandler.Config, level *slog.LevelVar) *levelController {
+	return &levelController{
+		level:        level,
+		defaultLevel: cfg.Level,
+	}
+}
+
+// set changes the minimum level to `level`. If `revertAfter` is greater than zero, the default level is restored
+// once it elapses.
+func (c *levelController) set(level slog.Level, revertAfter time.Duration) levelState {
+	c.mu.Lock()
+	previous := c.setLocked(level, revertAfter)
+	state := c.stateLocked()
+	c.mu.Unlock()
+	logLevelChange(previous, level, revertAfter)
+	return state
+}
+
+// toggle switches the minimum level between DEBUG and the default level. If the default level is DEBUG, it
+// switches between INFO and DEBUG instead, so toggling always has an effect.
+func (c *levelController) toggle(revertAfter time.Duration) levelState {
+	c.mu.Lock()
+	level := slog.LevelDebug
+	if c.level.Level() == slog.LevelDebug {
+		level = c.defaultLevel
+		if level == slog.LevelDebug {
+			level = slog.LevelInfo
+		}
+	}
+	previous := c.setLocked(level, revertAfter)
+	state := c.stateLocked()
+	c.mu.Unlock()
+	logLevelChange(previous, level, revertAfter)
+	return state
+}
+
+// reset restores the default level.
+func (c *levelController) reset() levelState {
+	return c.set(c.defaultLevel, 0)
+}
+
+func (c *levelController) state() levelState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stateLocked()
+}
+
+func (c *levelController) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopTimerLocked()
+}
+
+// setLocked changes the minimum level to `level`, returning the previous one.
+func (c *levelController) setLocked(level slog.Level, revertAfter time.Duration) slog.Level {
+	c.stopTimerLocked()
+	previous := c.level.Level()
+	c.level.Set(level)
+	if revertAfter > 0 && level != c.defaultLevel {
+		c.revertAt = time.Now().Add(revertAfter)
+		var timer *time.Timer
+		timer = time.AfterFunc(revertAfter, func() {
+			c.mu.Lock()
+			if c.revertTimer != timer {
+				// level was changed again after this timer fired
+				c.mu.Unlock()
+				return
+			}
+			previous := c.setLocked(c.defaultLevel, 0)
+			c.mu.Unlock()
+			logLevelChange(previous, c.defaultLevel, 0)
+		})
+		c.revertTimer = timer
+	}
+	return previous
+}
+
+func (c *levelController) stopTimerLocked() {
+	if c.revertTimer != nil {
+		c.revertTimer.Stop()
+		c.revertTimer = nil
+	}
+	c.revertAt = time.Time{}
+}
+
+func (c *levelController) stateLocked() levelState {
+	state := levelState{
+		Level:        c.level.Level().String(),
+		DefaultLevel: c.defaultLevel.String(),
+	}
+	if !c.revertAt.IsZero() {
+		revertAt := c.revertAt
+		state.RevertAt = &revertAt
+	}
+	return state
+}
+
+// logLevelChange logs a minimum level change. Called without holding the controller lock, as loggers may be
+// slow (e.g. writing to a file).
+func logLevelChange(previous, level slog.Level, revertAfter time.Duration) {
+	globallog.Logger().Warn("changed log level",
+		slog.String("previous", previous.String()),
+		slog.String("level", level.String()),
+		slog.Duration("revert_after", revertAfter),
+	)
+}
@@ -0,0 +1,81 @@
+package loggingfx
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/loghandler"
+)
+
+func TestLevelController(t *testing.T) {
+	cfg := loghandler.Config{Level: slog.LevelInfo}
+	level := newLevel(cfg)
+	ctrl := newLevelController(cfg, level)
+	defer ctrl.reset()
+
+	state := ctrl.toggle(0)
+	assert.Equal(t, "DEBUG", state.Level)
+	assert.Equal(t, slog.LevelDebug, level.Level())
+	// shared with enclave internal logger
+	assert.Equal(t, slog.LevelDebug, globallog.Level().Level())
+	state = ctrl.toggle(0)
+	assert.Equal(t, "INFO", state.Level)
+
+	// Auto-revert
+	state = ctrl.set(slog.LevelDebug, 20*time.Millisecond)
+	assert.NotNil(t, state.RevertAt)
+	assert.Eventually(t, func() bool {
+		return ctrl.state().Level == "INFO"
+	}, time.Second, 5*time.Millisecond)
+	assert.Nil(t, ctrl.state().RevertAt)
+
+	// Toggling from the DEBUG default level
+	cfg = loghandler.Config{Level: slog.LevelDebug}
+	ctrl = newLevelController(cfg, newLevel(cfg))
+	assert.Equal(t, "INFO", ctrl.toggle(0).Level)
+	assert.Equal(t, "DEBUG", ctrl.toggle(0).Level)
+}
+
+func TestController(t *testing.T) {
+	cfg := loghandler.Config{Level: slog.LevelInfo}
+	ctrl := newLevelController(cfg, newLevel(cfg))
+	e := echo.New()
+	e.HTTPErrorHandler = geckhttp.NewErrorHandler("json")
+	newController(levelConfig{AdminPath: "/admin/log-level"}, ctrl, nil).SetAdminEndpoints(e.Group(""), e.Group(""))
+
+	newRequest := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, newRequest(http.MethodPut, `{"level":"LOUD"}`).Code)
+
+	rec := newRequest(http.MethodPut, `{"level":"debug","revert_after":"1m"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"level":"DEBUG"`)
+	assert.Contains(t, rec.Body.String(), `"revert_at"`)
+
+	rec = newRequest(http.MethodDelete, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"level":"INFO"`)
+
+	// Served by the HTTP server if the admin server is disabled, requiring the admin token
+	e = echo.New()
+	e.HTTPErrorHandler = geckhttp.NewErrorHandler("json")
+	newController(levelConfig{AdminPath: "/admin/log-level"}, ctrl, nil).SetEndpoints(e)
+	assert.Equal(t, http.StatusUnauthorized, newRequest(http.MethodGet, "").Code)
+}
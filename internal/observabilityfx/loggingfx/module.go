@@ -1,15 +1,93 @@
 package loggingfx
 
 import (
+	"log/slog"
+
 	"go.uber.org/fx"
 
-	"github.com/bosonicalio/geck/observability/logging"
+	"github.com/bosonicalio/geck/application"
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/loghandler"
+	"github.com/bosonicalio/enclave/internal/osenv"
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
 )
 
-// ModuleSlog is the `uber/fx` module of the [logging] package, using
-// stdlib `slog` package for concrete implementations.
+// ModuleSlog is the `uber/fx` module of the application logger, using stdlib `slog` package for
+// concrete implementations.
+//
+// The logger format (`LOG_FORMAT`: json, text or pretty), minimum level (`LOG_LEVEL`), source location
+// (`LOG_ADD_SOURCE`), output (`LOG_OUTPUT`: stdout, stderr or a rotated file) and redacted attributes
+// (`LOG_REDACT_KEYS`, `LOG_REDACT_VALUE_PATTERNS`) are configured through environment variables, shared with
+// enclave internal logger.
+//
+// The minimum level is shared with enclave internal logger and can be changed at runtime, either sending SIGUSR1
+// (toggles DEBUG, or INFO if the configured level is DEBUG) and SIGUSR2 (restores the configured level) signals or
+// through the `LOG_LEVEL_ADMIN_PATH` endpoint, requiring `ADMIN_TOKEN` as bearer token. The endpoint is served by
+// the admin server if enabled (see [adminfx.Module]), or the HTTP server module otherwise. Changes are reverted
+// after `LOG_LEVEL_REVERT_AFTER`, if set.
+//
+// Every record is enriched with the application name, version, environment and instance ID (`app` group), along
+// with correlation fields from the record context (trace, span, request, tenant and user IDs, see
+// [github.com/bosonicalio/enclave/logging.WithFields]). Enclave internal logging is routed through the
+// application logger.
 var ModuleSlog = fx.Module("enclave/observability/logging/slog",
 	fx.Provide(
-		logging.NewSlogLogger,
+		osenv.ParseAs[loghandler.Config],
+		osenv.ParseAs[levelConfig],
+		newLevel,
+		newLogger,
+		newLevelController,
+		fx.Annotate(
+			newController,
+			fx.ParamTags("", "", `optional:"true"`), // admin server is optional
+			fx.As(new(geckhttp.Controller)),
+			fx.ResultTags(`group:"http_controllers"`),
+		),
+		fx.Annotate(
+			newController,
+			fx.ParamTags("", "", `optional:"true"`),
+			fx.As(new(adminfx.Controller)),
+			fx.ResultTags(`group:"admin_controllers"`),
+		),
+	),
+	fx.Invoke(
+		registerGlobalLogger,
+		watchLevelSignals,
 	),
 )
+
+// Configs are the configurations parsed by [ModuleSlog].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[loghandler.Config](""),
+	osenv.ConfigOf[levelConfig](""),
+}
+
+// -- Factory --
+
+// newLogger allocates the application logger, adding the application metadata to every record.
+func newLogger(cfg loghandler.Config, level *slog.LevelVar, app application.Application) (*slog.Logger, error) {
+	handler, err := loghandler.New(cfg, level)
+	if err != nil {
+		return nil, err
+	}
+	return slog.New(handler).With(slog.Group("app",
+		slog.String("name", app.Name),
+		slog.String("version", app.Version.String()),
+		slog.String("environment", app.Environment.String()),
+		slog.String("instance_id", app.InstanceID),
+	)), nil
+}
+
+// -- Invoker --
+
+// registerGlobalLogger routes enclave internal logging through the application logger, so both share their
+// format and attributes. Log files are closed once the application stops.
+func registerGlobalLogger(lc fx.Lifecycle, logger *slog.Logger) {
+	globallog.SetLogger(logger)
+	lc.Append(fx.StopHook(func() error {
+		globallog.SetLogger(nil)
+		return loghandler.Close()
+	}))
+}
@@ -0,0 +1,55 @@
+package loggingfx
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/bosonicalio/geck/environment"
+	"github.com/bosonicalio/geck/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/loghandler"
+)
+
+func TestNewLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	cfg := loghandler.Config{
+		Format:      "json",
+		Level:       slog.LevelInfo,
+		Output:      "file",
+		FilePath:    path,
+		FileMaxSize: 1,
+	}
+	logger, err := newLogger(cfg, newLevel(cfg), application.Application{
+		Name:        "orders",
+		Version:     version.MustParse("v1.2.3"),
+		Environment: environment.Production,
+		InstanceID:  "instance-1",
+	})
+	require.NoError(t, err)
+
+	lc := fxtest.NewLifecycle(t)
+	registerGlobalLogger(lc, logger)
+	assert.Same(t, logger, globallog.Logger())
+	globallog.Logger().Info("starting http server")
+	lc.RequireStart().RequireStop()
+	assert.NotSame(t, logger, globallog.Logger())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, map[string]any{
+		"name":        "orders",
+		"version":     "v1.2.3",
+		"environment": "production",
+		"instance_id": "instance-1",
+	}, record["app"])
+}
@@ -0,0 +1,46 @@
+package loggingfx
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"go.uber.org/fx"
+)
+
+// watchLevelSignals toggles the log level between DEBUG and the default level (INFO if the default level is
+// DEBUG) on SIGUSR1, and restores the default level on SIGUSR2. Not supported on platforms without these signals
+// (e.g. Windows).
+func watchLevelSignals(lc fx.Lifecycle, cfg levelConfig, level *levelController) {
+	lc.Append(fx.StopHook(level.stop))
+	if !cfg.EnableSignals || _toggleLevelSignal == nil {
+		return
+	}
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			signal.Notify(signals, _toggleLevelSignal, _resetLevelSignal)
+			go func() {
+				for {
+					select {
+					case sig := <-signals:
+						if sig == _toggleLevelSignal {
+							level.toggle(cfg.RevertAfter)
+						} else {
+							level.reset()
+						}
+					case <-done:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			signal.Stop(signals)
+			close(done)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package loggingfx
+
+import "os"
+
+var (
+	_toggleLevelSignal os.Signal
+	_resetLevelSignal  os.Signal
+)
@@ -0,0 +1,13 @@
+//go:build unix
+
+package loggingfx
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	_toggleLevelSignal os.Signal = syscall.SIGUSR1
+	_resetLevelSignal  os.Signal = syscall.SIGUSR2
+)
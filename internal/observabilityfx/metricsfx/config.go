@@ -0,0 +1,7 @@
+package metricsfx
+
+type config struct {
+	Path               string            `env:"METRICS_PATH" envDefault:"/metrics" validate:"required,startswith=/"`
+	EnableRuntime      bool              `env:"METRICS_ENABLE_RUNTIME" envDefault:"true"`
+	ResourceAttributes map[string]string `env:"METRICS_RESOURCE_ATTRIBUTES"`
+}
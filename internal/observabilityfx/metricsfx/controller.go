@@ -0,0 +1,53 @@
+package metricsfx
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
+)
+
+type controller struct {
+	cfg      config
+	registry *prometheus.Registry
+	admin    *adminfx.Server
+}
+
+// compile-time assertions
+var (
+	_ geckhttp.Controller = (*controller)(nil)
+	_ adminfx.Controller  = (*controller)(nil)
+)
+
+func newController(cfg config, registry *prometheus.Registry, admin *adminfx.Server) controller {
+	return controller{
+		cfg:      cfg,
+		registry: registry,
+		admin:    admin,
+	}
+}
+
+func (c controller) SetEndpoints(e *echo.Echo) {
+	if c.admin.Enabled() {
+		// served by the admin server
+		return
+	}
+	e.GET(c.cfg.Path, echo.WrapHandler(newHandler(c.registry)))
+}
+
+func (c controller) SetVersionedEndpoints(_ *echo.Group) {}
+
+func (c controller) SetAdminEndpoints(public, _ *echo.Group) {
+	public.GET(c.cfg.Path, echo.WrapHandler(newHandler(c.registry)))
+}
+
+func newHandler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		Registry: registry,
+	})
+}
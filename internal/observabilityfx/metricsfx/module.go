@@ -0,0 +1,129 @@
+package metricsfx
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	runtimemetrics "go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.uber.org/fx"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/internal/observabilityfx"
+	"github.com/bosonicalio/enclave/internal/osenv"
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
+)
+
+const _meterName = "github.com/bosonicalio/enclave/internal/observabilityfx/metricsfx"
+
+// Module is the `uber/fx` module for application metrics, using OpenTelemetry with a Prometheus exporter.
+//
+// It provides a [metric.MeterProvider] whose metrics are exposed in the Prometheus format at `METRICS_PATH`
+// (defaults to `/metrics`), through the admin server if enabled (see [adminfx.Module]) or the HTTP server module
+// otherwise. The provider is also registered globally.
+//
+// Go runtime metrics (`METRICS_ENABLE_RUNTIME`) and an `app_info` gauge describing the application are
+// recorded by default. HTTP server and SQL modules record their own metrics if this module is present.
+var Module = fx.Module("enclave/observability/metrics",
+	fx.Provide(
+		osenv.ParseAs[config],
+		newRegistry,
+		fx.Annotate(
+			newMeterProvider,
+			fx.As(fx.Self()),
+			fx.As(new(metric.MeterProvider)),
+		),
+		fx.Annotate(
+			newController,
+			fx.ParamTags("", "", `optional:"true"`), // admin server is optional
+			fx.As(new(geckhttp.Controller)),
+			fx.ResultTags(`group:"http_controllers"`),
+		),
+		fx.Annotate(
+			newController,
+			fx.ParamTags("", "", `optional:"true"`),
+			fx.As(new(adminfx.Controller)),
+			fx.ResultTags(`group:"admin_controllers"`),
+		),
+	),
+	fx.Invoke(
+		registerGlobalMeterProvider,
+		startRuntimeMetrics,
+		registerAppInfo,
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// -- Factory --
+
+func newRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return registry
+}
+
+func newMeterProvider(lc fx.Lifecycle, cfg config, app application.Application,
+	registry *prometheus.Registry) (*sdkmetric.MeterProvider, error) {
+	res, err := observabilityfx.NewResource(app, cfg.ResourceAttributes)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, err
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(exporter),
+	)
+	lc.Append(fx.StopHook(mp.Shutdown))
+	return mp, nil
+}
+
+// -- Invoker --
+
+func registerGlobalMeterProvider(mp metric.MeterProvider) {
+	otel.SetMeterProvider(mp)
+}
+
+func startRuntimeMetrics(cfg config, mp metric.MeterProvider) error {
+	if !cfg.EnableRuntime {
+		return nil
+	}
+	return runtimemetrics.Start(runtimemetrics.WithMeterProvider(mp))
+}
+
+// registerAppInfo records the `app_info` gauge, always set to 1 and labelled with the application metadata, so it
+// can be joined with other metrics.
+func registerAppInfo(mp metric.MeterProvider, app application.Application) error {
+	attrs := metric.WithAttributes(
+		attribute.String("name", app.Name),
+		attribute.String("environment", app.Environment.String()),
+		attribute.String("version", app.Version.String()),
+		attribute.String("instance_id", app.InstanceID),
+		attribute.Int("cpus", runtime.NumCPU()),
+		attribute.String("go_version", runtime.Version()),
+		attribute.String("go_os", runtime.GOOS),
+		attribute.String("go_arch", runtime.GOARCH),
+	)
+	_, err := mp.Meter(_meterName).Int64ObservableGauge("app_info",
+		metric.WithDescription("Application metadata."),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(1, attrs)
+			return nil
+		}),
+	)
+	return err
+}
@@ -0,0 +1,65 @@
+package metricsfx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/bosonicalio/geck/environment"
+	"github.com/bosonicalio/geck/version"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
+)
+
+func TestModule(t *testing.T) {
+	app := application.Application{
+		Name:        "orders",
+		Version:     version.MustParse("v1.2.3"),
+		Environment: environment.Production,
+		InstanceID:  "instance-1",
+	}
+	cfg := config{Path: "/metrics", EnableRuntime: true}
+	registry := newRegistry()
+	lc := fxtest.NewLifecycle(t)
+	mp, err := newMeterProvider(lc, cfg, app, registry)
+	require.NoError(t, err)
+	require.NoError(t, startRuntimeMetrics(cfg, mp))
+	require.NoError(t, registerAppInfo(mp, app))
+
+	e := echo.New()
+	newController(cfg, registry, nil).SetEndpoints(e)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `app_info{`)
+	assert.Contains(t, string(body), `name="orders"`)
+	assert.Contains(t, string(body), `version="v1.2.3"`)
+	assert.Contains(t, string(body), `go_goroutine`)
+	assert.Contains(t, string(body), `process_cpu_seconds_total`)
+
+	// Served by the admin server
+	t.Setenv("ADMIN_ADDRESS", "localhost:9090")
+	var admin *adminfx.Server
+	fxtest.New(t, adminfx.Module, fx.Populate(&admin))
+	ctrl := newController(cfg, registry, admin)
+	e = echo.New()
+	ctrl.SetEndpoints(e)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	e = echo.New()
+	ctrl.SetAdminEndpoints(e.Group(""), e.Group(""))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	lc.RequireStart().RequireStop()
+}
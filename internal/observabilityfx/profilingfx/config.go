@@ -0,0 +1,13 @@
+package profilingfx
+
+import "time"
+
+type config struct {
+	BlockProfileRate     int               `env:"PROFILING_BLOCK_PROFILE_RATE" envDefault:"1000000" validate:"gte=0"`
+	MutexProfileFraction int               `env:"PROFILING_MUTEX_PROFILE_FRACTION" envDefault:"100" validate:"gte=0"`
+	PushEndpoint         string            `env:"PROFILING_PUSH_ENDPOINT" validate:"omitempty,url"`
+	PushInterval         time.Duration     `env:"PROFILING_PUSH_INTERVAL" envDefault:"15s" validate:"gte=1s"`
+	PushTimeout          time.Duration     `env:"PROFILING_PUSH_TIMEOUT" envDefault:"10s" validate:"gt=0"`
+	PushHeaders          map[string]string `env:"PROFILING_PUSH_HEADERS" secret:"true"`
+	PushTags             map[string]string `env:"PROFILING_PUSH_TAGS"`
+}
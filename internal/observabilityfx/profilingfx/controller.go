@@ -0,0 +1,35 @@
+package profilingfx
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
+)
+
+type controller struct{}
+
+// compile-time assertion
+var _ adminfx.Controller = (*controller)(nil)
+
+func newController() controller {
+	return controller{}
+}
+
+func (c controller) SetAdminEndpoints(_, protected *echo.Group) {
+	protected.Any("/debug/pprof/*", echo.WrapHandler(newHandler()))
+}
+
+// newHandler returns the `net/http/pprof` handlers (CPU, heap, goroutine, block, mutex, etc.) under
+// `/debug/pprof/`.
+func newHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
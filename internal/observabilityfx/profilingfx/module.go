@@ -0,0 +1,63 @@
+package profilingfx
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/bosonicalio/geck/application"
+	"go.uber.org/fx"
+
+	"github.com/bosonicalio/enclave/internal/osenv"
+	"github.com/bosonicalio/enclave/internal/transportfx/adminfx"
+)
+
+// Module is the `uber/fx` module for application profiling.
+//
+// It serves `net/http/pprof` handlers (CPU, heap, goroutine, block, mutex, etc.) under `/debug/pprof/` on the
+// admin server, if enabled (see [adminfx.Module]), requiring the admin token. Block and mutex profiles are
+// sampled as configured by `PROFILING_BLOCK_PROFILE_RATE` and `PROFILING_MUTEX_PROFILE_FRACTION` (0 disables
+// them).
+//
+// If `PROFILING_PUSH_ENDPOINT` is set, CPU and heap profiles are continuously collected and pushed every
+// `PROFILING_PUSH_INTERVAL` to a Pyroscope-compatible server, labelled with the application metadata and
+// `PROFILING_PUSH_TAGS`.
+var Module = fx.Module("enclave/observability/profiling",
+	fx.Provide(
+		osenv.ParseAs[config],
+		fx.Annotate(
+			newController,
+			fx.As(new(adminfx.Controller)),
+			fx.ResultTags(`group:"admin_controllers"`),
+		),
+	),
+	fx.Invoke(
+		setProfileRates,
+		startPusher,
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// -- Invoker --
+
+func setProfileRates(cfg config) {
+	runtime.SetBlockProfileRate(cfg.BlockProfileRate)
+	runtime.SetMutexProfileFraction(cfg.MutexProfileFraction)
+}
+
+func startPusher(lc fx.Lifecycle, cfg config, app application.Application) {
+	if cfg.PushEndpoint == "" {
+		return
+	}
+	p := newPusher(cfg, app)
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			p.start()
+			return nil
+		},
+		OnStop: p.shutdown,
+	})
+}
@@ -0,0 +1,70 @@
+package profilingfx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/bosonicalio/geck/environment"
+	"github.com/bosonicalio/geck/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler(t *testing.T) {
+	handler := newHandler()
+	for _, profile := range []string{"heap", "goroutine", "block", "mutex"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/"+profile, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, profile)
+		assert.NotEmpty(t, rec.Body.Bytes(), profile)
+	}
+}
+
+func TestPusher(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []*http.Request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := r.FormFile("profile"); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	p := newPusher(config{
+		PushEndpoint: server.URL,
+		PushInterval: 100 * time.Millisecond,
+		PushTimeout:  time.Second,
+		PushHeaders:  map[string]string{"X-Scope-OrgID": "tenant-1"},
+		PushTags:     map[string]string{"region": "us-east-1"},
+	}, application.Application{
+		Name:        "orders",
+		Version:     version.MustParse("v1.2.3"),
+		Environment: environment.Production,
+		InstanceID:  "instance-1",
+	})
+	p.start()
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, p.shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	// cpu and heap profiles per cycle
+	require.GreaterOrEqual(t, len(requests), 2)
+	req := requests[0]
+	assert.Equal(t, "/ingest", req.URL.Path)
+	assert.Equal(t, "orders{environment="+environment.Production.String()+
+		",instance_id=instance-1,region=us-east-1,version=v1.2.3}", req.URL.Query().Get("name"))
+	assert.Equal(t, "pprof", req.URL.Query().Get("format"))
+	assert.Equal(t, "tenant-1", req.Header.Get("X-Scope-OrgID"))
+}
@@ -0,0 +1,156 @@
+package profilingfx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bosonicalio/geck/application"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+)
+
+// pusher periodically collects CPU and heap profiles, pushing them to a Pyroscope-compatible ingestion
+// endpoint (`POST /ingest`).
+type pusher struct {
+	client   *http.Client
+	endpoint string
+	name     string
+	interval time.Duration
+	headers  map[string]string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newPusher(cfg config, app application.Application) *pusher {
+	tags := map[string]string{
+		"environment": app.Environment.String(),
+		"version":     app.Version.String(),
+		"instance_id": app.InstanceID,
+	}
+	maps.Copy(tags, cfg.PushTags)
+	labels := make([]string, 0, len(tags))
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		labels = append(labels, key+"="+tags[key])
+	}
+	return &pusher{
+		client:   &http.Client{Timeout: cfg.PushTimeout},
+		endpoint: strings.TrimSuffix(cfg.PushEndpoint, "/") + "/ingest",
+		name:     app.Name + "{" + strings.Join(labels, ",") + "}",
+		interval: cfg.PushInterval,
+		headers:  cfg.PushHeaders,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (p *pusher) start() {
+	go p.run()
+}
+
+// shutdown stops collecting profiles, waiting for the last ones to be pushed.
+func (p *pusher) shutdown(ctx context.Context) error {
+	close(p.stop)
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *pusher) run() {
+	defer close(p.done)
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+	for {
+		from := time.Now()
+		cpu := &bytes.Buffer{}
+		// fails if a CPU profile is already being collected (e.g. through the admin server)
+		errCPU := pprof.StartCPUProfile(cpu)
+		stopped := false
+		select {
+		case <-timer.C:
+			timer.Reset(p.interval)
+		case <-p.stop:
+			stopped = true
+		}
+		if errCPU == nil {
+			pprof.StopCPUProfile()
+		}
+		until := time.Now()
+
+		if errCPU != nil {
+			globallog.Logger().Warn("skipped cpu profile push", slog.String("error", errCPU.Error()))
+		} else {
+			p.push(cpu, from, until)
+		}
+		heap := &bytes.Buffer{}
+		if err := pprof.Lookup("heap").WriteTo(heap, 0); err != nil {
+			globallog.Logger().Warn("skipped heap profile push", slog.String("error", err.Error()))
+		} else {
+			p.push(heap, from, until)
+		}
+		if stopped {
+			return
+		}
+	}
+}
+
+func (p *pusher) push(profile io.Reader, from, until time.Time) {
+	if err := p.upload(profile, from, until); err != nil {
+		globallog.Logger().Error("failed to push profile", slog.String("error", err.Error()))
+	}
+}
+
+func (p *pusher) upload(profile io.Reader, from, until time.Time) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(part, profile); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("name", p.name)
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("until", strconv.FormatInt(until.Unix(), 10))
+	query.Set("format", "pprof")
+	query.Set("spyName", "gospy")
+	req, err := http.NewRequest(http.MethodPost, p.endpoint+"?"+query.Encode(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("enclave.profiling: unexpected ingestion response status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Package observabilityfx contains helpers shared by observability modules (tracing, metrics, etc.).
+package observabilityfx
+
+import (
+	"github.com/bosonicalio/geck/application"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// NewResource allocates the OpenTelemetry resource describing `app` (name, version, environment and instance ID),
+// along with `attributes` (e.g. `TRACING_RESOURCE_ATTRIBUTES`).
+func NewResource(app application.Application, attributes map[string]string) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(app.Name),
+		semconv.ServiceVersion(app.Version.String()),
+		semconv.DeploymentEnvironmentName(app.Environment.String()),
+		semconv.ServiceInstanceID(app.InstanceID),
+	}
+	for key, value := range attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	return resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+}
@@ -0,0 +1,13 @@
+package tracingfx
+
+import "time"
+
+type config struct {
+	Exporter           string            `env:"TRACING_EXPORTER" envDefault:"otlp_grpc" validate:"oneof=otlp_grpc otlp_http none"`
+	Endpoint           string            `env:"TRACING_ENDPOINT"`
+	Insecure           bool              `env:"TRACING_INSECURE"`
+	Headers            map[string]string `env:"TRACING_HEADERS" secret:"true"`
+	Timeout            time.Duration     `env:"TRACING_TIMEOUT" envDefault:"10s" validate:"gt=0"`
+	SamplerRatio       float64           `env:"TRACING_SAMPLER_RATIO" envDefault:"1" validate:"gte=0,lte=1"`
+	ResourceAttributes map[string]string `env:"TRACING_RESOURCE_ATTRIBUTES"`
+}
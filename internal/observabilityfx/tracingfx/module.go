@@ -0,0 +1,113 @@
+package tracingfx
+
+import (
+	"context"
+
+	"github.com/bosonicalio/geck/application"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+
+	"github.com/bosonicalio/enclave/internal/observabilityfx"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Module is the `uber/fx` module for distributed tracing, using OpenTelemetry.
+//
+// It provides a [trace.TracerProvider] exporting spans through OTLP (gRPC or HTTP, see `TRACING_EXPORTER`),
+// sampled with a parent-based ratio sampler (`TRACING_SAMPLER_RATIO`). Spans are described by the application
+// name, version, environment and instance ID. The provider is also registered globally, along with W3C trace
+// context and baggage propagators.
+//
+// HTTP server, SQL and AWS modules instrument their operations if this module is present.
+var Module = fx.Module("enclave/observability/tracing",
+	fx.Provide(
+		osenv.ParseAs[config],
+		newExporter,
+		newResource,
+		fx.Annotate(
+			newTracerProvider,
+			fx.As(fx.Self()),
+			fx.As(new(trace.TracerProvider)),
+		),
+	),
+	fx.Invoke(
+		registerGlobalTracerProvider,
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// -- Factory --
+
+func newExporter(cfg config) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+	switch cfg.Exporter {
+	case "otlp_http":
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithTimeout(cfg.Timeout),
+		}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case "otlp_grpc":
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithTimeout(cfg.Timeout),
+		}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, nil
+	}
+}
+
+func newResource(cfg config, app application.Application) (*resource.Resource, error) {
+	return observabilityfx.NewResource(app, cfg.ResourceAttributes)
+}
+
+func newTracerProvider(lc fx.Lifecycle, cfg config, res *resource.Resource,
+	exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplerRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	lc.Append(fx.StopHook(tp.Shutdown))
+	return tp
+}
+
+// -- Invoker --
+
+func registerGlobalTracerProvider(tp trace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
@@ -0,0 +1,46 @@
+package tracingfx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/bosonicalio/geck/environment"
+	"github.com/bosonicalio/geck/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	app := application.Application{
+		Name:        "orders",
+		Version:     version.MustParse("v1.2.3"),
+		Environment: environment.Production,
+		InstanceID:  "instance-1",
+	}
+	cfg := config{
+		SamplerRatio:       1,
+		ResourceAttributes: map[string]string{"team": "checkout"},
+	}
+	res, err := newResource(cfg, app)
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	lc := fxtest.NewLifecycle(t)
+	tp := newTracerProvider(lc, cfg, res, exporter)
+	_, span := tp.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Resource.Attributes()
+	assert.Contains(t, attrs, attribute.String("service.name", "orders"))
+	assert.Contains(t, attrs, attribute.String("service.version", "v1.2.3"))
+	assert.Contains(t, attrs, attribute.String("service.instance.id", "instance-1"))
+	assert.Contains(t, attrs, attribute.String("team", "checkout"))
+	lc.RequireStart().RequireStop()
+}
@@ -0,0 +1,52 @@
+package osenv
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+)
+
+// ConfigSpec is a configuration structure type parsed by a module with [ParseAs] or [ParseAsWithPrefix].
+//
+// Modules list their configuration specs, so configurations are checked (see [Check]) and described (see
+// [Describe]) without building the modules.
+type ConfigSpec struct {
+	Type   reflect.Type
+	Prefix string
+}
+
+// ConfigOf returns the [ConfigSpec] of struct type `T` parsed with `prefix`.
+func ConfigOf[T any](prefix string) ConfigSpec {
+	return ConfigSpec{Type: reflect.TypeFor[T](), Prefix: prefix}
+}
+
+// Check parses every configuration of `configs`, returning an [*Error] listing the invalid environment variables
+// of all of them, or nil.
+//
+// This allows reporting the configuration errors of every module at once, while dependency injection containers
+// stop at the first failing constructor.
+func Check(configs ...ConfigSpec) error {
+	var variables []VariableError
+	checked := make([]ConfigSpec, 0, len(configs))
+	for _, cfg := range configs {
+		if slices.Contains(checked, cfg) {
+			continue
+		}
+		checked = append(checked, cfg)
+		err := parse(reflect.New(cfg.Type).Interface(), cfg.Prefix)
+		var errConfig *Error
+		if !errors.As(err, &errConfig) {
+			continue
+		}
+		for _, variable := range errConfig.Variables {
+			// configurations may share variables
+			if !slices.Contains(variables, variable) {
+				variables = append(variables, variable)
+			}
+		}
+	}
+	if len(variables) == 0 {
+		return nil
+	}
+	return &Error{Variables: variables}
+}
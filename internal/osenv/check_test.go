@@ -0,0 +1,41 @@
+package osenv
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	type sqlConfig struct {
+		ConnectionString string `env:"TEST_SQL_CONNECTION_STRING" validate:"required"`
+	}
+	type httpConfig struct {
+		Address string `env:"TEST_HTTP_ADDRESS" validate:"required,hostname_port"`
+	}
+	t.Setenv("TEST_HTTP_ADDRESS", "localhost")
+
+	err := Check(ConfigOf[sqlConfig](""), ConfigOf[sqlConfig](""), ConfigOf[httpConfig](""))
+	var errConfig *Error
+	require.True(t, errors.As(err, &errConfig))
+	assert.Equal(t, []VariableError{
+		{Name: "TEST_SQL_CONNECTION_STRING", Message: "is required"},
+		{Name: "TEST_HTTP_ADDRESS", Message: "must be a valid host:port address"},
+	}, errConfig.Variables)
+
+	report := &bytes.Buffer{}
+	require.NoError(t, errConfig.WriteReport(report))
+	assert.Equal(t, `invalid configuration (2 errors):
+
+  VARIABLE                    ERROR
+  TEST_SQL_CONNECTION_STRING  is required
+  TEST_HTTP_ADDRESS           must be a valid host:port address
+`, report.String())
+
+	t.Setenv("TEST_SQL_CONNECTION_STRING", "postgres://localhost")
+	t.Setenv("TEST_HTTP_ADDRESS", "localhost:8080")
+	assert.NoError(t, Check(ConfigOf[sqlConfig](""), ConfigOf[httpConfig]("")))
+}
@@ -0,0 +1,47 @@
+package osenv
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// VariableSpec describes an environment variable loaded into a configuration structure.
+type VariableSpec struct {
+	// Name is the environment variable name, including prefixes (e.g. `HTTP_SERVER_ADDRESS`).
+	Name string
+	// Type is the Go type of the value (e.g. `time.Duration`).
+	Type string
+	// Default is the value used if the variable is not set (`envDefault` struct tag).
+	Default    string
+	HasDefault bool
+	// Required indicates the variable must be set (`required` validation rule or `env` tag option).
+	Required bool
+	// Rules are the validation rules of the value (`validate` struct tag).
+	Rules string
+	// Secret indicates the value is a secret (`secret:"true"` struct tag), so it must not be displayed.
+	Secret bool
+}
+
+// Describe describes the environment variables loaded into struct type `t` with `prefix` (see [ParseAsWithPrefix]).
+func Describe(t reflect.Type, prefix string) []VariableSpec {
+	fields := fieldsOf(t, prefix)
+	specs := make([]VariableSpec, 0, len(fields))
+	for _, f := range fields {
+		_, options, _ := strings.Cut(f.Field.Tag.Get("env"), ",")
+		rules := f.Field.Tag.Get("validate")
+		defaultValue, hasDefault := f.Field.Tag.Lookup("envDefault")
+		specs = append(specs, VariableSpec{
+			Name:       f.Key,
+			Type:       f.Field.Type.String(),
+			Default:    defaultValue,
+			HasDefault: hasDefault,
+			Required: slices.Contains(strings.Split(rules, ","), "required") ||
+				slices.Contains(strings.Split(options, ","), "required") ||
+				slices.Contains(strings.Split(options, ","), "notEmpty"),
+			Rules:  rules,
+			Secret: f.Field.Tag.Get("secret") == "true",
+		})
+	}
+	return specs
+}
@@ -0,0 +1,106 @@
+package osenv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// VariableError is an invalid environment variable.
+type VariableError struct {
+	// Name is the environment variable name (e.g. `HTTP_SERVER_ADDRESS`).
+	Name string
+	// Message describes why the value is invalid (e.g. `is required`). Never contains the value itself, as it might
+	// be a secret.
+	Message string
+}
+
+func (e VariableError) Error() string {
+	return e.Name + " " + e.Message
+}
+
+// Error is the aggregated error of a configuration, listing every invalid environment variable.
+type Error struct {
+	Variables []VariableError
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Variables))
+	for _, variable := range e.Variables {
+		msgs = append(msgs, variable.Error())
+	}
+	return "enclave.config: invalid configuration: " + strings.Join(msgs, "; ")
+}
+
+// WriteReport writes a human-readable report of the invalid environment variables to `w`, as a table.
+func (e *Error) WriteReport(w io.Writer) error {
+	noun := "errors"
+	if len(e.Variables) == 1 {
+		noun = "error"
+	}
+	if _, err := fmt.Fprintf(w, "invalid configuration (%d %s):\n\n", len(e.Variables), noun); err != nil {
+		return err
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(table, "  VARIABLE\tERROR")
+	for _, variable := range e.Variables {
+		name := variable.Name
+		if name == "" {
+			name = "-"
+		}
+		_, _ = fmt.Fprintf(table, "  %s\t%s\n", name, variable.Message)
+	}
+	return table.Flush()
+}
+
+// Unwrap returns the [VariableError] list.
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, len(e.Variables))
+	for _, variable := range e.Variables {
+		errs = append(errs, variable)
+	}
+	return errs
+}
+
+// newVariableErrors converts `err` returned by [env.ParseWithOptions] with `environment` into variable errors.
+func newVariableErrors(err error, fields []field, environment map[string]string) []VariableError {
+	var errAggregate env.AggregateError
+	if !errors.As(err, &errAggregate) {
+		return []VariableError{{Message: err.Error()}}
+	}
+	variables := make([]VariableError, 0, len(errAggregate.Errors))
+	reported := make(map[string]struct{}, len(errAggregate.Errors))
+	for _, errField := range errAggregate.Errors {
+		var (
+			errNotSet   env.VarIsNotSetError
+			errEmpty    env.EmptyVarError
+			errParse    env.ParseError
+			errLoadFile env.LoadFileContentError
+		)
+		switch {
+		case errors.As(errField, &errNotSet):
+			variables = append(variables, VariableError{Name: errNotSet.Key, Message: "is required"})
+		case errors.As(errField, &errEmpty):
+			variables = append(variables, VariableError{Name: errEmpty.Key, Message: "must not be empty"})
+		case errors.As(errField, &errParse):
+			key := failedKeyOf(fields, errParse.Name, environment, reported)
+			reported[key] = struct{}{}
+			variables = append(variables, VariableError{
+				Name:    key,
+				Message: fmt.Sprintf("is not a valid %s: %v", errParse.Type, errParse.Err),
+			})
+		case errors.As(errField, &errLoadFile):
+			variables = append(variables, VariableError{
+				Name:    errLoadFile.Key,
+				Message: fmt.Sprintf("could not be loaded from file %s: %v", errLoadFile.Filename, errLoadFile.Err),
+			})
+		default:
+			variables = append(variables, VariableError{Message: errField.Error()})
+		}
+	}
+	return variables
+}
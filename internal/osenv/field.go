@@ -0,0 +1,93 @@
+package osenv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// field is a struct field loaded from an environment variable.
+type field struct {
+	// Namespace is the field path from the root struct (e.g. `Database.Host`).
+	Namespace string
+	// Name is the Go field name (e.g. `Host`).
+	Name string
+	// Key is the environment variable name, including prefixes (e.g. `DB_HOST`).
+	Key string
+	// Field is the struct field.
+	Field reflect.StructField
+}
+
+// fieldsOf lists the fields of struct type `t` loaded from environment variables, following `env` and
+// `envPrefix` tags like [github.com/caarlos0/env/v11] does.
+func fieldsOf(t reflect.Type, prefix string) []field {
+	return appendFields(nil, t, prefix, "")
+}
+
+func appendFields(fields []field, t reflect.Type, prefix, namespace string) []field {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldNamespace := sf.Name
+		if namespace != "" {
+			fieldNamespace = namespace + "." + sf.Name
+		}
+		key, _, _ := strings.Cut(sf.Tag.Get("env"), ",")
+		if key == "" || key == "-" {
+			fields = appendFields(fields, sf.Type, prefix+sf.Tag.Get("envPrefix"), fieldNamespace)
+			continue
+		}
+		fields = append(fields, field{
+			Namespace: fieldNamespace,
+			Name:      sf.Name,
+			Key:       prefix + key,
+			Field:     sf,
+		})
+	}
+	return fields
+}
+
+// failedKeyOf returns the environment variable of the field named `name` failing to parse, or `name` if not
+// found. [env.ParseError] only identifies the Go field name, so fields sharing it (e.g. `Primary.Port` and
+// `Replica.Port`) are told apart by parsing each one alone from `environment`. Keys in `skip` (i.e. already
+// reported) are ignored.
+func failedKeyOf(fields []field, name string, environment map[string]string, skip map[string]struct{}) string {
+	var candidates []field
+	for _, f := range fields {
+		if _, found := skip[f.Key]; f.Name == name && !found {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return name
+	}
+	for _, f := range candidates {
+		if !parsesAlone(f, environment) {
+			return f.Key
+		}
+	}
+	return candidates[0].Key
+}
+
+// parsesAlone reports whether field `f` parses from `environment` on its own.
+func parsesAlone(f field, environment map[string]string) bool {
+	key, _, _ := strings.Cut(f.Field.Tag.Get("env"), ",")
+	t := reflect.StructOf([]reflect.StructField{{
+		Name: f.Field.Name,
+		Type: f.Field.Type,
+		Tag:  reflect.StructTag(strings.Replace(string(f.Field.Tag), `env:"`+key, `env:"`+f.Key, 1)),
+	}})
+	err := env.ParseWithOptions(reflect.New(t).Interface(), env.Options{Environment: environment})
+	var errParse env.ParseError
+	return !errors.As(err, &errParse)
+}
@@ -2,6 +2,7 @@ package osenv
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -9,17 +10,48 @@ import (
 // ParseAs parses the given struct type containing `env` tags and loads its
 // values from environment variables.
 //
-// Based on [env.ParseAs], extends the functionality to validate the
-// parsed structure using the global validator.
+// Based on [env.ParseAs], extends the functionality to resolve secret
+// references of fields tagged with `secret:"true"` (see
+// [RegisterSecretResolver]) and validate the parsed structure with its
+// `validate` tags.
+//
+// Returns an [*Error] listing every invalid environment variable.
 func ParseAs[T any]() (T, error) {
-	st, err := env.ParseAs[T]()
-	if err != nil {
-		var zeroVal T
-		return zeroVal, err
-	}
-	if err = GlobalValidator().Validate(context.Background(), st); err != nil {
+	return ParseAsWithPrefix[T]("")
+}
+
+// ParseAsWithPrefix is [ParseAs], prepending `prefix` to every environment
+// variable name (e.g. `ORDERS_` loads `DB_HOST` from `ORDERS_DB_HOST`).
+func ParseAsWithPrefix[T any](prefix string) (T, error) {
+	var st T
+	if err := parse(&st, prefix); err != nil {
 		var zeroVal T
 		return zeroVal, err
 	}
 	return st, nil
 }
+
+// parse loads the struct pointed by `v` from environment variables with `prefix`, returning an [*Error] if any
+// of them is invalid.
+func parse(v any, prefix string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _secretsTimeout)
+	defer cancel()
+	fields := fieldsOf(reflect.TypeOf(v), prefix)
+	environment, variables := resolveSecrets(ctx, fields)
+	err := env.ParseWithOptions(v, env.Options{
+		Environment: environment,
+		Prefix:      prefix,
+	})
+	if err != nil {
+		variables = append(variables, newVariableErrors(err, fields, environment)...)
+	}
+	reported := make(map[string]struct{}, len(variables))
+	for _, variable := range variables {
+		reported[variable.Name] = struct{}{}
+	}
+	variables = append(variables, validate(ctx, v, fields, reported)...)
+	if len(variables) > 0 {
+		return &Error{Variables: variables}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package osenv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAs_NestedFieldNames(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+	type config struct {
+		Primary database `envPrefix:"TEST_PRIMARY_"`
+		Replica database `envPrefix:"TEST_REPLICA_"`
+	}
+	t.Setenv("TEST_PRIMARY_PORT", "5432")
+	t.Setenv("TEST_REPLICA_PORT", "replica")
+
+	_, err := ParseAs[config]()
+	var errConfig *Error
+	require.True(t, errors.As(err, &errConfig), err)
+	require.Len(t, errConfig.Variables, 1)
+	assert.Equal(t, "TEST_REPLICA_PORT", errConfig.Variables[0].Name)
+
+	t.Setenv("TEST_PRIMARY_PORT", "primary")
+	_, err = ParseAs[config]()
+	require.True(t, errors.As(err, &errConfig), err)
+	names := make([]string, 0, len(errConfig.Variables))
+	for _, variable := range errConfig.Variables {
+		names = append(names, variable.Name)
+	}
+	assert.ElementsMatch(t, []string{"TEST_PRIMARY_PORT", "TEST_REPLICA_PORT"}, names)
+}
@@ -0,0 +1,135 @@
+package osenv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// SecretResolver resolves the value of secret references of a URI scheme (e.g. `file:///run/secrets/db`).
+type SecretResolver interface {
+	// ResolveSecret resolves the value of `ref`, excluding its fragment.
+	ResolveSecret(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// SecretResolverFunc is a function implementing [SecretResolver].
+type SecretResolverFunc func(ctx context.Context, ref *url.URL) (string, error)
+
+// compile-time assertion
+var _ SecretResolver = SecretResolverFunc(nil)
+
+// ResolveSecret calls f(ctx, ref).
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, ref *url.URL) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	_secretResolversMu sync.RWMutex
+	_secretResolvers   = map[string]SecretResolver{
+		"file": SecretResolverFunc(resolveFileSecret),
+	}
+	// _secrets caches resolved secrets by reference, so they are fetched once per process.
+	_secrets sync.Map
+)
+
+// _secretsTimeout is the maximum duration of the secret resolutions of a configuration structure.
+const _secretsTimeout = 30 * time.Second
+
+// RegisterSecretResolver registers `resolver` for references with `scheme` (e.g. `awssm`), replacing any
+// previous one.
+//
+// The `file` scheme is registered by default, reading the value from a file (e.g. `file:///run/secrets/db`).
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	_secretResolversMu.Lock()
+	defer _secretResolversMu.Unlock()
+	_secretResolvers[scheme] = resolver
+}
+
+func lookupSecretResolver(scheme string) (SecretResolver, bool) {
+	_secretResolversMu.RLock()
+	defer _secretResolversMu.RUnlock()
+	resolver, ok := _secretResolvers[scheme]
+	return resolver, ok
+}
+
+// resolveSecrets returns environment variables with secret references used by `fields` replaced by their values,
+// along with an error per unresolved reference.
+//
+// Only fields tagged with `secret:"true"` are resolved, so other values (e.g. endpoint URLs) are never mistaken for
+// references. A reference is a value with a registered scheme (see [RegisterSecretResolver]). If it has a fragment (e.g.
+// `awssm://db-credentials#password`), the secret is decoded as a JSON object and the value of the fragment key is
+// used.
+func resolveSecrets(ctx context.Context, fields []field) (map[string]string, []VariableError) {
+	environment := env.ToMap(os.Environ())
+	var variables []VariableError
+	for _, f := range fields {
+		if f.Field.Tag.Get("secret") != "true" {
+			continue
+		}
+		value, ok := environment[f.Key]
+		if !ok || !strings.Contains(value, "://") {
+			continue
+		}
+		ref, err := url.Parse(value)
+		if err != nil {
+			continue
+		}
+		resolver, ok := lookupSecretResolver(ref.Scheme)
+		if !ok {
+			continue
+		}
+		secret, err := resolveSecret(ctx, resolver, ref)
+		if err != nil {
+			variables = append(variables, VariableError{
+				Name:    f.Key,
+				Message: "secret could not be resolved: " + err.Error(),
+			})
+			continue
+		}
+		environment[f.Key] = secret
+	}
+	return environment, variables
+}
+
+func resolveSecret(ctx context.Context, resolver SecretResolver, ref *url.URL) (string, error) {
+	if secret, ok := _secrets.Load(ref.String()); ok {
+		return secret.(string), nil
+	}
+	key := ref.Fragment
+	secretRef := *ref
+	secretRef.Fragment, secretRef.RawFragment = "", ""
+	secret, err := resolver.ResolveSecret(ctx, &secretRef)
+	if err != nil {
+		return "", err
+	}
+	if key != "" {
+		var fields map[string]any
+		if err = json.Unmarshal([]byte(secret), &fields); err != nil {
+			return "", fmt.Errorf("secret is not a JSON object: %w", err)
+		}
+		field, ok := fields[key]
+		if !ok {
+			return "", fmt.Errorf("secret key %q not found", key)
+		}
+		secret = formatValue(field)
+	}
+	_secrets.Store(ref.String(), secret)
+	return secret, nil
+}
+
+// resolveFileSecret reads the secret from the path of `ref`, trimming trailing new lines (e.g. Docker and
+// Kubernetes secrets).
+func resolveFileSecret(_ context.Context, ref *url.URL) (string, error) {
+	data, err := os.ReadFile(ref.Path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
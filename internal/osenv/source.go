@@ -0,0 +1,232 @@
+package osenv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigFileEnv is the environment variable holding the path of the configuration file. If not set, files named
+	// `config.yaml`, `config.yml`, `config.toml` or `config.json` are searched in the working directory.
+	ConfigFileEnv = "ENCLAVE_CONFIG_FILE"
+	// DotEnvFilesEnv is the environment variable holding a comma-separated list of `.env` files (defaults to `.env`).
+	DotEnvFilesEnv = "ENCLAVE_DOTENV_FILES"
+	// EnvironmentEnv is the environment variable holding the application environment, selecting
+	// environment-specific files (e.g. `config.staging.yaml`, `.env.staging`).
+	EnvironmentEnv = "ENCLAVE_APP_ENVIRONMENT"
+)
+
+var _configFileExts = []string{".yaml", ".yml", ".toml", ".json"}
+
+// Load loads configuration values from files into environment variables, so they are parsed by [ParseAs] (and
+// third-party components reading the environment) like any other variable.
+//
+// Sources are merged in the following priority order, from highest to lowest:
+//
+//   - Environment variables.
+//   - Environment-specific `.env` files (e.g. `.env.staging`, selected by `ENCLAVE_APP_ENVIRONMENT`).
+//   - `.env` files (see [DotEnvFilesEnv]).
+//   - Environment-specific configuration file (e.g. `config.staging.yaml`, next to the configuration file).
+//   - Configuration file (YAML, TOML or JSON, see [ConfigFileEnv]).
+//   - Defaults (`envDefault` struct tags).
+//
+// Configuration files are flattened into environment variable names, joining nested keys with underscores
+// (e.g. `http: {server: {address: ":8080"}}` sets `HTTP_SERVER_ADDRESS`) while lists are joined with commas.
+// Keys may also be written as environment variable names (e.g. `HTTP_SERVER_ADDRESS: ":8080"`).
+//
+// Missing files are skipped, except the configuration file if set explicitly.
+func Load() error {
+	dotEnvFiles := []string{".env"}
+	if files, ok := os.LookupEnv(DotEnvFilesEnv); ok {
+		dotEnvFiles = splitList(files)
+	}
+	dotEnv, err := readDotEnvFiles(dotEnvFiles)
+	if err != nil {
+		return err
+	}
+
+	configFile, explicit := lookup(ConfigFileEnv, dotEnv)
+	if !explicit {
+		configFile = findConfigFile(".", "config")
+	}
+	config := map[string]string{}
+	if configFile != "" {
+		fileConfig, errFile := readConfigFile(configFile)
+		if errFile != nil && (explicit || !errors.Is(errFile, fs.ErrNotExist)) {
+			return errFile
+		}
+		maps.Copy(config, fileConfig)
+	}
+
+	values := config
+	if environment, ok := lookup(EnvironmentEnv, dotEnv, config); ok && environment != "" {
+		envConfig, errEnv := readEnvironmentConfigFile(configFile, environment)
+		if errEnv != nil {
+			return errEnv
+		}
+		maps.Copy(values, envConfig)
+		envDotEnvFiles := make([]string, 0, len(dotEnvFiles))
+		for _, file := range dotEnvFiles {
+			envDotEnvFiles = append(envDotEnvFiles, file+"."+environment)
+		}
+		envDotEnv, errEnv := readDotEnvFiles(envDotEnvFiles)
+		if errEnv != nil {
+			return errEnv
+		}
+		maps.Copy(dotEnv, envDotEnv)
+	}
+	maps.Copy(values, dotEnv)
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		if err = os.Setenv(key, values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup looks `key` up in environment variables, then in `sources` in order.
+func lookup(key string, sources ...map[string]string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+	for _, source := range sources {
+		if value, ok := source[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func splitList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readDotEnvFiles reads `files`, skipping missing ones. Values of latter files take precedence.
+func readDotEnvFiles(files []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, file := range files {
+		fileValues, err := godotenv.Read(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("enclave.config: failed to read %s: %w", file, err)
+		}
+		maps.Copy(values, fileValues)
+	}
+	return values, nil
+}
+
+// findConfigFile returns the first existing `name` file in `dir` with a supported extension.
+func findConfigFile(dir, name string) string {
+	for _, ext := range _configFileExts {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// readEnvironmentConfigFile reads the `environment` variant of `configFile` (e.g. `config.staging.yaml`), if any.
+func readEnvironmentConfigFile(configFile, environment string) (map[string]string, error) {
+	var path string
+	if configFile != "" {
+		ext := filepath.Ext(configFile)
+		path = strings.TrimSuffix(configFile, ext) + "." + environment + ext
+	} else if path = findConfigFile(".", "config."+environment); path == "" {
+		return map[string]string{}, nil
+	}
+	values, err := readConfigFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	return values, err
+}
+
+// readConfigFile reads a YAML, TOML or JSON file (based on its extension), flattening it into environment
+// variables.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("enclave.config: failed to read %s: %w", path, err)
+	}
+	var doc map[string]any
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("enclave.config: unsupported file format %q of %s", ext, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("enclave.config: failed to parse %s: %w", path, err)
+	}
+	values := map[string]string{}
+	flatten(values, "", doc)
+	return values, nil
+}
+
+func flatten(values map[string]string, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			flatten(values, joinKey(prefix, key), item)
+		}
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatValue(item))
+		}
+		values[prefix] = strings.Join(items, ",")
+	case nil:
+		values[prefix] = ""
+	default:
+		values[prefix] = formatValue(v)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	key = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+	if prefix == "" {
+		return key
+	}
+	return prefix + "_" + key
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
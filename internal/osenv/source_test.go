@@ -0,0 +1,108 @@
+package osenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unsetenv unsets `keys` for the test duration.
+func unsetenv(t *testing.T, keys ...string) {
+	for _, key := range keys {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.yaml"), `
+enclave:
+  app:
+    environment: staging
+http:
+  server:
+    address: ":8080"
+    read-timeout: 5s
+    cors_allow_origins: [https://a.example.com, https://b.example.com]
+LOG_LEVEL: DEBUG
+FROM_FILE: base
+FROM_ENV_FILE: base
+FROM_DOTENV: base
+FROM_OS: base
+`)
+	writeFile(t, filepath.Join(dir, "config.staging.yaml"), `
+FROM_ENV_FILE: staging
+FROM_DOTENV: staging
+`)
+	writeFile(t, filepath.Join(dir, ".env"), "FROM_DOTENV=dotenv\nFROM_ENV_DOTENV=dotenv\n")
+	writeFile(t, filepath.Join(dir, ".env.staging"), "FROM_ENV_DOTENV=staging\n")
+
+	t.Setenv(ConfigFileEnv, filepath.Join(dir, "config.yaml"))
+	t.Setenv(DotEnvFilesEnv, filepath.Join(dir, ".env"))
+	t.Setenv("FROM_OS", "os")
+	unsetenv(t, EnvironmentEnv, "HTTP_SERVER_ADDRESS", "HTTP_SERVER_READ_TIMEOUT", "HTTP_SERVER_CORS_ALLOW_ORIGINS",
+		"LOG_LEVEL", "FROM_FILE", "FROM_ENV_FILE", "FROM_DOTENV", "FROM_ENV_DOTENV")
+
+	require.NoError(t, Load())
+	expected := map[string]string{
+		EnvironmentEnv:                   "staging",
+		"HTTP_SERVER_ADDRESS":            ":8080",
+		"HTTP_SERVER_READ_TIMEOUT":       "5s",
+		"HTTP_SERVER_CORS_ALLOW_ORIGINS": "https://a.example.com,https://b.example.com",
+		"LOG_LEVEL":                      "DEBUG",
+		"FROM_FILE":                      "base",
+		"FROM_ENV_FILE":                  "staging",
+		"FROM_DOTENV":                    "dotenv",
+		"FROM_ENV_DOTENV":                "staging",
+		"FROM_OS":                        "os",
+	}
+	for key, value := range expected {
+		assert.Equal(t, value, os.Getenv(key), key)
+	}
+
+	type config struct {
+		Address string `env:"HTTP_SERVER_ADDRESS"`
+		Port    int    `env:"HTTP_SERVER_PORT" envDefault:"80"`
+	}
+	cfg, err := ParseAs[config]()
+	require.NoError(t, err)
+	assert.Equal(t, config{Address: ":8080", Port: 80}, cfg)
+}
+
+func TestLoad_Formats(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "toml", file: "config.toml", content: "[http.server]\naddress = \":8080\"\nmax_header_bytes = 1048576\n"},
+		{name: "json", file: "config.json", content: `{"http": {"server": {"address": ":8080", "max_header_bytes": 1048576}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFile(t, filepath.Join(dir, tt.file), tt.content)
+			t.Setenv(ConfigFileEnv, filepath.Join(dir, tt.file))
+			t.Setenv(DotEnvFilesEnv, "")
+			unsetenv(t, EnvironmentEnv, "HTTP_SERVER_ADDRESS", "HTTP_SERVER_MAX_HEADER_BYTES")
+
+			require.NoError(t, Load())
+			assert.Equal(t, ":8080", os.Getenv("HTTP_SERVER_ADDRESS"))
+			assert.Equal(t, "1048576", os.Getenv("HTTP_SERVER_MAX_HEADER_BYTES"))
+		})
+	}
+}
+
+func TestLoad_MissingConfigFile(t *testing.T) {
+	t.Setenv(ConfigFileEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv(DotEnvFilesEnv, "")
+	assert.Error(t, Load())
+}
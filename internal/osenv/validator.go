@@ -1,25 +1,102 @@
 package osenv
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/bosonicalio/geck/validation"
+	"github.com/go-playground/validator/v10"
 )
 
 var (
-	_globalValidatorOnce sync.Once
-	_globalValidator     validation.Validator
+	_validatorOnce sync.Once
+	_validator     *validator.Validate
 )
 
-// GlobalValidator returns a global validator for environment variables.
-func GlobalValidator() validation.Validator {
-	_globalValidatorOnce.Do(func() {
-		_globalValidator = validation.NewGoPlaygroundValidator(
-			validation.WithCodecDriver(validation.EnvironmentDriver),
-			validation.WithRules(
-				validation.NewDateRule(),
-			),
-		)
+func globalValidator() *validator.Validate {
+	_validatorOnce.Do(func() {
+		_validator = validator.New()
+		dateRule := validation.NewDateRule()
+		_ = _validator.RegisterValidation(dateRule.Name, func(fl validator.FieldLevel) bool {
+			return dateRule.ValidateFunc(dateRule.Name, fl.Field().Interface())
+		})
 	})
-	return _globalValidator
+	return _validator
+}
+
+// validate validates `v` with its `validate` struct tags, returning an error per invalid environment variable.
+// Variables in `skip` (i.e. already reported) are ignored.
+func validate(ctx context.Context, v any, fields []field, skip map[string]struct{}) []VariableError {
+	err := globalValidator().StructCtx(ctx, v)
+	if err == nil {
+		return nil
+	}
+	var errsValidation validator.ValidationErrors
+	if !errors.As(err, &errsValidation) {
+		return []VariableError{{Message: err.Error()}}
+	}
+	keys := make(map[string]string, len(fields))
+	for _, f := range fields {
+		keys[f.Namespace] = f.Key
+	}
+	variables := make([]VariableError, 0, len(errsValidation))
+	for _, errValidation := range errsValidation {
+		// struct namespace starts with the root struct name
+		_, namespace, _ := strings.Cut(errValidation.StructNamespace(), ".")
+		name, ok := keys[namespace]
+		if !ok {
+			name = namespace
+		}
+		if _, found := skip[name]; found {
+			continue
+		}
+		variables = append(variables, VariableError{
+			Name:    name,
+			Message: validationMessage(errValidation),
+		})
+	}
+	return variables
+}
+
+func validationMessage(err validator.FieldError) string {
+	param := err.Param()
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "eq", "eq_ignore_case":
+		return "must be equal to " + param
+	case "ne", "ne_ignore_case":
+		return "must not be equal to " + param
+	case "len":
+		return "must have a length of " + param
+	case "min", "gte":
+		return "must be at least " + param
+	case "gt":
+		return "must be greater than " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "lt":
+		return "must be less than " + param
+	case "url":
+		return "must be a valid URL"
+	case "hostname_port":
+		return "must be a valid host:port address"
+	case "startswith":
+		return "must start with " + param
+	case "date":
+		return "must be a date (YYYY-MM-DD)"
+	default:
+		if strings.Contains(err.Tag(), "|") {
+			return "must satisfy one of: " + strings.ReplaceAll(err.Tag(), "|", ", ")
+		}
+		if param != "" {
+			return fmt.Sprintf("must satisfy %s=%s", err.Tag(), param)
+		}
+		return "must satisfy " + err.Tag()
+	}
 }
@@ -1,7 +1,7 @@
 package persistencefx
 
 type tokenConfig struct {
-	CipherKey string `env:"PAGE_TOKEN_CIPHER_KEY" validate:"omitempty,len=16|len=24|len=32"`
+	CipherKey string `env:"PAGE_TOKEN_CIPHER_KEY" validate:"omitempty,len=16|len=24|len=32" secret:"true"`
 }
 
 type identifierConfig struct {
@@ -33,6 +33,12 @@ var Module = fx.Module("enclave/persistence",
 	),
 )
 
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[tokenConfig](""),
+	osenv.ConfigOf[identifierConfig](""),
+}
+
 // -- Factory --
 
 func newTokenCipherKey(config tokenConfig) (paging.TokenCipherKey, error) {
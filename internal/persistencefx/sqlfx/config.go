@@ -13,7 +13,7 @@ type Config struct {
 	TxContextIsolationLevel sql.IsolationLevel `env:"SQL_TX_CONTEXT_ISOLATION_LEVEL" validate:"omitempty,gte=1|lte=7"`
 	TxContextReadOnly       bool               `env:"SQL_TX_CONTEXT_READ_ONLY"`
 
-	ConnectionString   string        `env:"SQL_CONNECTION_STRING" validate:"required"`
+	ConnectionString   string        `env:"SQL_CONNECTION_STRING" validate:"required" secret:"true"`
 	MaxConnections     int32         `env:"SQL_MAX_CONNECTIONS" validate:"omitempty,gte=0"`
 	MinConnections     int32         `env:"SQL_MIN_CONNECTIONS" validate:"omitempty,gte=0"`
 	MinIdleConnections int32         `env:"SQL_MIN_IDLE_CONNECTIONS" validate:"omitempty,gte=0"`
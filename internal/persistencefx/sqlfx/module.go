@@ -5,8 +5,10 @@ import (
 	"log/slog"
 
 	gecksql "github.com/bosonicalio/geck/persistence/sql"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 
+	"github.com/bosonicalio/enclave/health"
 	"github.com/bosonicalio/enclave/internal/osenv"
 )
 
@@ -15,30 +17,39 @@ import (
 
 // Module provides the SQL database connection and configuration for the enclave persistence layer.
 // It uses the [gecksql] package to manage SQL database interactions, with optional logging and transaction
-// context propagation.
+// context propagation. Statements are traced if the tracing module is present.
 // The module is configured via environment variables, allowing for flexible deployment configurations.
 var Module = fx.Module("enclave/persistence/sql",
 	fx.Provide(
 		osenv.ParseAs[Config],
 		fx.Annotate(
 			newDB,
-			fx.ParamTags("", "", `optional:"true"`), // logger is optional
+			fx.ParamTags("", "", `optional:"true"`, `optional:"true"`), // logger and tracer provider are optional
 		),
 		newTxFactory,
+		health.AsChecker(newHealthChecker),
 	),
 	fx.Invoke(
 		registerTxFactory,
 	),
 )
 
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[Config](""),
+}
+
 // -- Factory --
 
-func newDB(cfg Config, db *sql.DB, logger *slog.Logger) gecksql.DB {
-	if !cfg.EnableLogging && !cfg.EnableTxContext {
+func newDB(cfg Config, db *sql.DB, logger *slog.Logger, tp trace.TracerProvider) gecksql.DB {
+	if !cfg.EnableLogging && !cfg.EnableTxContext && tp == nil {
 		return db
 	}
 	// NOTE: Order of decorators matters. Last is the first to be applied.
 	var aggregateDB = gecksql.DB(db)
+	if tp != nil {
+		aggregateDB = newDBTracer(aggregateDB, tp)
+	}
 	if cfg.EnableTxContext {
 		aggregateDB = gecksql.NewDBTxPropagator(aggregateDB)
 	}
@@ -56,3 +67,9 @@ func newTxFactory(db gecksql.DB, cfg Config) gecksql.TxFactory {
 		ReadOnly:  cfg.TxContextReadOnly,
 	})
 }
+
+// newHealthChecker checks the database through the underlying connection pool, so probes are neither traced nor
+// logged.
+func newHealthChecker(db *sql.DB) health.Checker {
+	return health.NewChecker("sql", db.PingContext)
+}
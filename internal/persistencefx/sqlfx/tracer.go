@@ -0,0 +1,92 @@
+package sqlfx
+
+import (
+	"context"
+	"database/sql"
+
+	gecksql "github.com/bosonicalio/geck/persistence/sql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const _tracerName = "github.com/bosonicalio/enclave/internal/persistencefx/sqlfx"
+
+// dbTracer is an interceptor component adhering tracing capabilities to an existing [gecksql.DB].
+//
+// Statements executed within transactions (i.e. through [sql.Tx]) are not traced.
+type dbTracer struct {
+	next   gecksql.DB
+	tracer trace.Tracer
+}
+
+// compile-time assertion
+var _ gecksql.DB = (*dbTracer)(nil)
+
+func newDBTracer(parent gecksql.DB, tp trace.TracerProvider) dbTracer {
+	return dbTracer{
+		next:   parent,
+		tracer: tp.Tracer(_tracerName),
+	}
+}
+
+func (d dbTracer) start(ctx context.Context, operation, query string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.DBOperationName(operation),
+	}
+	if query != "" {
+		attrs = append(attrs, semconv.DBQueryText(query))
+	}
+	return d.tracer.Start(ctx, "sql."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (d dbTracer) Begin() (*sql.Tx, error) {
+	return d.BeginTx(context.Background(), nil)
+}
+
+func (d dbTracer) BeginTx(ctx context.Context, opts *sql.TxOptions) (tx *sql.Tx, err error) {
+	ctx, span := d.start(ctx, "begin", "")
+	defer func() { endSpan(span, err) }()
+	return d.next.BeginTx(ctx, opts)
+}
+
+func (d dbTracer) QueryContext(ctx context.Context, query string, args ...any) (rows *sql.Rows, err error) {
+	ctx, span := d.start(ctx, "query", query)
+	defer func() { endSpan(span, err) }()
+	return d.next.QueryContext(ctx, query, args...)
+}
+
+func (d dbTracer) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := d.start(ctx, "query_row", query)
+	row := d.next.QueryRowContext(ctx, query, args...)
+	var err error
+	if row != nil {
+		err = row.Err()
+	}
+	endSpan(span, err)
+	return row
+}
+
+func (d dbTracer) ExecContext(ctx context.Context, query string, args ...any) (res sql.Result, err error) {
+	ctx, span := d.start(ctx, "exec", query)
+	defer func() { endSpan(span, err) }()
+	return d.next.ExecContext(ctx, query, args...)
+}
+
+func (d dbTracer) PrepareContext(ctx context.Context, query string) (stmt *sql.Stmt, err error) {
+	ctx, span := d.start(ctx, "prepare", query)
+	defer func() { endSpan(span, err) }()
+	return d.next.PrepareContext(ctx, query)
+}
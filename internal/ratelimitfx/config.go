@@ -0,0 +1,17 @@
+package ratelimitfx
+
+import "time"
+
+type config struct {
+	Algorithm  string        `env:"RATE_LIMIT_ALGORITHM" envDefault:"token_bucket" validate:"required,oneof=token_bucket sliding_window"`
+	Store      string        `env:"RATE_LIMIT_STORE" envDefault:"memory" validate:"required,oneof=memory sql"`
+	Requests   int           `env:"RATE_LIMIT_REQUESTS" envDefault:"100" validate:"gt=0"`
+	Window     time.Duration `env:"RATE_LIMIT_WINDOW" envDefault:"1m" validate:"gt=0"`
+	Burst      int           `env:"RATE_LIMIT_BURST" validate:"gte=0"`
+	KeySource  string        `env:"RATE_LIMIT_KEY_SOURCE" envDefault:"ip" validate:"required,oneof=ip header principal"`
+	KeyHeader  string        `env:"RATE_LIMIT_KEY_HEADER" validate:"required_if=KeySource header"`
+	PerRoute   bool          `env:"RATE_LIMIT_PER_ROUTE"`
+	SkipPaths  []string      `env:"RATE_LIMIT_SKIP_PATHS"`
+	SQLTable   string        `env:"RATE_LIMIT_SQL_TABLE" envDefault:"enclave_rate_limits" validate:"required"`
+	SQLMigrate bool          `env:"RATE_LIMIT_SQL_AUTO_MIGRATE" envDefault:"true"`
+}
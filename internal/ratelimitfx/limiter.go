@@ -0,0 +1,142 @@
+package ratelimitfx
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// state is the stored state of a rate limited key.
+//
+// Its meaning depends on the limiting algorithm:
+//   - Token bucket: Count is the number of available tokens and Timestamp the last refill time.
+//   - Sliding window: Count and PrevCount are the number of requests performed during the current and previous
+//     windows, Timestamp is the start of the current window.
+type state struct {
+	Count     float64
+	PrevCount float64
+	Timestamp time.Time
+}
+
+// store is a storage for rate limited keys state.
+type store interface {
+	// update atomically replaces the state of `key` with the value returned by `fn`. `found` is false if
+	// there is no state stored for `key` (or it expired). The new state expires after `ttl`.
+	update(ctx context.Context, key string, ttl time.Duration, fn func(current state, found bool) state) error
+}
+
+// decision is the result of a rate limit evaluation.
+type decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// limiter evaluates whether a request identified by a key must be allowed.
+type limiter interface {
+	allow(ctx context.Context, key string) (decision, error)
+}
+
+// -- Token Bucket --
+
+// tokenBucketLimiter allows bursts of up to `capacity` requests, refilling `rate` tokens every `window`.
+type tokenBucketLimiter struct {
+	store    store
+	capacity float64
+	rate     float64
+	window   time.Duration
+	now      func() time.Time
+}
+
+// compile-time assertion
+var _ limiter = (*tokenBucketLimiter)(nil)
+
+func (l tokenBucketLimiter) allow(ctx context.Context, key string) (decision, error) {
+	var result decision
+	refillPerSecond := l.rate / l.window.Seconds()
+	ttl := time.Duration(l.capacity/refillPerSecond*float64(time.Second)) + time.Second
+	err := l.store.update(ctx, key, ttl, func(current state, found bool) state {
+		now := l.now()
+		tokens := l.capacity
+		if found {
+			elapsed := now.Sub(current.Timestamp).Seconds()
+			tokens = math.Min(l.capacity, current.Count+math.Max(0, elapsed)*refillPerSecond)
+		}
+		result = decision{
+			Allowed: tokens >= 1,
+			Limit:   int(l.capacity),
+		}
+		if result.Allowed {
+			tokens--
+		} else {
+			result.RetryAfter = time.Duration((1 - tokens) / refillPerSecond * float64(time.Second))
+		}
+		result.Remaining = int(tokens)
+		return state{
+			Count:     tokens,
+			Timestamp: now,
+		}
+	})
+	return result, err
+}
+
+// -- Sliding Window --
+
+// slidingWindowLimiter allows up to `limit` requests within any `window`, approximating the request count
+// with the weighted counts of the current and previous fixed windows.
+type slidingWindowLimiter struct {
+	store  store
+	limit  float64
+	window time.Duration
+	now    func() time.Time
+}
+
+// compile-time assertion
+var _ limiter = (*slidingWindowLimiter)(nil)
+
+func (l slidingWindowLimiter) allow(ctx context.Context, key string) (decision, error) {
+	var result decision
+	err := l.store.update(ctx, key, 2*l.window, func(current state, found bool) state {
+		now := l.now()
+		windowStart := now.Truncate(l.window)
+		next := state{
+			Timestamp: windowStart,
+		}
+		if found {
+			switch elapsedWindows := windowStart.Sub(current.Timestamp) / l.window; elapsedWindows {
+			case 0:
+				next = current
+			case 1:
+				next.PrevCount = current.Count
+			}
+		}
+
+		prevWeight := 1 - float64(now.Sub(windowStart))/float64(l.window)
+		count := next.PrevCount*prevWeight + next.Count
+		result = decision{
+			Allowed: count+1 <= l.limit,
+			Limit:   int(l.limit),
+		}
+		if result.Allowed {
+			next.Count++
+			count++
+		} else {
+			result.RetryAfter = l.retryAfter(now, windowStart, next)
+		}
+		result.Remaining = int(math.Max(0, l.limit-count))
+		return next
+	})
+	return result, err
+}
+
+// retryAfter computes the time left until the weighted count of `current` drops below the limit.
+func (l slidingWindowLimiter) retryAfter(now, windowStart time.Time, current state) time.Duration {
+	untilNextWindow := windowStart.Add(l.window).Sub(now)
+	if current.Count+1 > l.limit || current.PrevCount == 0 {
+		return untilNextWindow
+	}
+	// prevCount * (1 - elapsed/window) + count + 1 <= limit
+	elapsed := (1 - (l.limit-current.Count-1)/current.PrevCount) * float64(l.window)
+	return time.Duration(math.Max(0, elapsed-float64(now.Sub(windowStart))))
+}
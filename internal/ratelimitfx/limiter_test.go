@@ -0,0 +1,167 @@
+package ratelimitfx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	st := newMemoryStore()
+	st.now = clock.Now
+	limiter := tokenBucketLimiter{store: st, capacity: 2, rate: 1, window: time.Second, now: clock.Now}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.allow(ctx, "foo")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	result, err := limiter.allow(ctx, "foo")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// Other keys are not affected
+	result, err = limiter.allow(ctx, "bar")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Refill
+	clock.now = clock.now.Add(time.Second)
+	result, err = limiter.allow(ctx, "foo")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	st := newMemoryStore()
+	st.now = clock.Now
+	limiter := slidingWindowLimiter{store: st, limit: 2, window: time.Minute, now: clock.Now}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.allow(ctx, "foo")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	result, err := limiter.allow(ctx, "foo")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Minute, result.RetryAfter)
+
+	// Previous window requests still weight at the beginning of the next one
+	clock.now = clock.now.Add(time.Minute + 15*time.Second)
+	result, err = limiter.allow(ctx, "foo")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 15*time.Second, result.RetryAfter)
+
+	clock.now = clock.now.Add(15 * time.Second)
+	result, err = limiter.allow(ctx, "foo")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Expired windows are reset
+	clock.now = clock.now.Add(3 * time.Minute)
+	require.NoError(t, st.removeExpired(ctx))
+	assert.Empty(t, st.entries)
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := config{Requests: 1, Window: time.Minute, KeySource: "header", KeyHeader: "X-Api-Key"}
+	limiter, err := newLimiter(config{Algorithm: "token_bucket", Requests: 1, Window: time.Minute}, newMemoryStore())
+	require.NoError(t, err)
+	e := echo.New()
+	e.HTTPErrorHandler = geckhttp.NewErrorHandler("json")
+	e.Use(newMiddleware(cfg, limiter))
+	e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	newRequest := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("X-Api-Key", apiKey)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := newRequest("foo")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+
+	rec = newRequest("foo")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Contains(t, rec.Body.String(), "RATE_LIMIT_EXCEEDED")
+
+	rec = newRequest("bar")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestMiddleware_SpoofedForwardedFor(t *testing.T) {
+	cfg := config{Requests: 1, Window: time.Minute, KeySource: "ip"}
+	limiter, err := newLimiter(config{Algorithm: "token_bucket", Requests: 1, Window: time.Minute}, newMemoryStore())
+	require.NoError(t, err)
+	e := echo.New()
+	e.HTTPErrorHandler = geckhttp.NewErrorHandler("json")
+	// as configured by the HTTP server module without trusted proxies
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(newMiddleware(cfg, limiter))
+	e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	newRequest := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.RemoteAddr = "198.51.100.1:1234"
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusNoContent, newRequest("203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, newRequest("203.0.113.2"))
+}
+
+func TestRemoveExpiredPeriodically(t *testing.T) {
+	st := newMemoryStore()
+	require.NoError(t, st.update(context.Background(), "client", time.Millisecond, func(current state, _ bool) state {
+		return current
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		removeExpiredPeriodically(ctx, st, 5*time.Millisecond)
+	}()
+	assert.Eventually(t, func() bool {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		return len(st.entries) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "expired entries removal not stopped")
+	}
+}
@@ -0,0 +1,75 @@
+package ratelimitfx
+
+import (
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/geck/security/identity"
+	"github.com/bosonicalio/geck/syserr"
+
+	"github.com/bosonicalio/enclave/logging"
+)
+
+// errRateLimitExceeded is returned to clients when they exceeded the allowed request rate.
+var errRateLimitExceeded = syserr.New(syserr.ResourceExhausted, "too many requests",
+	syserr.WithInternalCode("RATE_LIMIT_EXCEEDED"),
+)
+
+// newMiddleware rate limits requests using `limiter`, identifying clients based on `cfg` key source.
+//
+// Rejected requests return [errRateLimitExceeded] (encoded by the HTTP server error handler) along with
+// the `Retry-After` header.
+func newMiddleware(cfg config, limiter limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(cfg.SkipPaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := newKey(cfg, c)
+			result, err := limiter.allow(ctx, key)
+			if err != nil {
+				// fail open, a storage outage must not take the whole application down
+				logging.FromContext(ctx).ErrorContext(ctx, "failed to evaluate rate limit, allowing request",
+					slog.String("error", err.Error()),
+				)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			if !result.Allowed {
+				retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(max(retryAfter, 1)))
+				return errRateLimitExceeded
+			}
+			return next(c)
+		}
+	}
+}
+
+// newKey builds the rate limit key of the request. Falls back to the client IP if the configured
+// key source is missing.
+func newKey(cfg config, c echo.Context) string {
+	key := "ip:" + c.RealIP()
+	switch cfg.KeySource {
+	case "header":
+		if value := c.Request().Header.Get(cfg.KeyHeader); value != "" {
+			key = "header:" + value
+		}
+	case "principal":
+		if principal, err := identity.GetPrincipal(c.Request().Context()); err == nil {
+			key = "principal:" + principal.ID()
+		}
+	}
+	if cfg.PerRoute {
+		key += "|" + c.Request().Method + " " + c.Path()
+	}
+	return key
+}
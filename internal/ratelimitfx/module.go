@@ -0,0 +1,142 @@
+package ratelimitfx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	gecksql "github.com/bosonicalio/geck/persistence/sql"
+	"go.uber.org/fx"
+
+	enclavehttp "github.com/bosonicalio/enclave/http"
+	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Module is the `uber/fx` module for HTTP server rate limiting.
+//
+// It registers a middleware limiting the request rate of each client (identified by IP, a request header or the
+// authenticated principal) using either a token bucket or a sliding window algorithm. The state is kept in memory
+// by default; set `RATE_LIMIT_STORE` to `sql` to share it between replicas through the SQL module database, which
+// must be PostgreSQL (checked on startup). Its table is created on startup unless `RATE_LIMIT_SQL_AUTO_MIGRATE` is
+// disabled.
+//
+// Client IPs are resolved by the HTTP server module, trusting forwarding headers only from
+// `HTTP_SERVER_TRUSTED_PROXIES`, so clients cannot get a fresh limit by spoofing them.
+var Module = fx.Module("enclave/ratelimit",
+	fx.Provide(
+		osenv.ParseAs[config],
+		fx.Annotate(
+			newStore,
+			fx.ParamTags("", "", `optional:"true"`), // database is only required by sql store
+		),
+		newLimiter,
+		enclavehttp.AsMiddleware(newMiddleware, enclavehttp.PriorityRateLimit),
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// -- Factory --
+
+type expirableStore interface {
+	store
+	removeExpired(ctx context.Context) error
+}
+
+func newStore(lc fx.Lifecycle, cfg config, db gecksql.DB) (store, error) {
+	var st expirableStore
+	switch cfg.Store {
+	case "memory":
+		st = newMemoryStore()
+	case "sql":
+		if db == nil {
+			return nil, errors.New("enclave.ratelimit: sql store requires the SQL module")
+		}
+		sqlSt, err := newSQLStore(db, cfg.SQLTable)
+		if err != nil {
+			return nil, err
+		}
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				if err := sqlSt.checkDatabase(ctx); err != nil {
+					return err
+				}
+				if cfg.SQLMigrate {
+					return sqlSt.migrate(ctx)
+				}
+				return nil
+			},
+		})
+		st = sqlSt
+	default:
+		return nil, errors.New("enclave.ratelimit: unsupported store")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				removeExpiredPeriodically(ctx, st, cfg.Window)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			// cancels in-flight removals too, waiting for them to return
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+	return st, nil
+}
+
+// removeExpiredPeriodically removes expired entries from `st` every `interval`, until `ctx` is canceled.
+func removeExpiredPeriodically(ctx context.Context, st expirableStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := st.removeExpired(ctx); err != nil && ctx.Err() == nil {
+				globallog.Logger().WarnContext(ctx, "failed to remove expired rate limit entries",
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
+
+func newLimiter(cfg config, st store) (limiter, error) {
+	switch cfg.Algorithm {
+	case "token_bucket":
+		return tokenBucketLimiter{
+			store:    st,
+			capacity: float64(max(cfg.Burst, cfg.Requests)),
+			rate:     float64(cfg.Requests),
+			window:   cfg.Window,
+			now:      time.Now,
+		}, nil
+	case "sliding_window":
+		return slidingWindowLimiter{
+			store:  st,
+			limit:  float64(cfg.Requests),
+			window: cfg.Window,
+			now:    time.Now,
+		}, nil
+	default:
+		return nil, errors.New("enclave.ratelimit: unsupported algorithm")
+	}
+}
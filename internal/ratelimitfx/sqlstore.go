@@ -0,0 +1,103 @@
+package ratelimitfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	gecksql "github.com/bosonicalio/geck/persistence/sql"
+)
+
+var _sqlIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlStore is a [store] keeping state in a SQL database table, sharing it between application replicas.
+//
+// Queries and migrations use PostgreSQL syntax (e.g. upserts with `RETURNING` clauses and `TIMESTAMPTZ` columns), so
+// the database is checked to be PostgreSQL on startup (see [sqlStore.checkDatabase]).
+type sqlStore struct {
+	db    gecksql.DB
+	table string
+	now   func() time.Time
+}
+
+// compile-time assertion
+var _ store = (*sqlStore)(nil)
+
+func newSQLStore(db gecksql.DB, table string) (*sqlStore, error) {
+	if !_sqlIdentifierRegexp.MatchString(table) {
+		return nil, fmt.Errorf("enclave.ratelimit: invalid sql table name %q", table)
+	}
+	return &sqlStore{
+		db:    db,
+		table: table,
+		now:   time.Now,
+	}, nil
+}
+
+// checkDatabase fails if the database is not PostgreSQL, so unsupported databases are reported on startup
+// instead of failing every request.
+func (s *sqlStore) checkDatabase(ctx context.Context) error {
+	var version string
+	if err := s.db.QueryRowContext(ctx, `SELECT version()`).Scan(&version); err != nil {
+		return fmt.Errorf("enclave.ratelimit: failed to check sql store database: %w", err)
+	}
+	if !strings.HasPrefix(version, "PostgreSQL") {
+		return fmt.Errorf("enclave.ratelimit: sql store requires a PostgreSQL database, got %q", version)
+	}
+	return nil
+}
+
+// migrate creates the rate limit table if it does not exist.
+func (s *sqlStore) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	key        TEXT PRIMARY KEY,
+	count      DOUBLE PRECISION NOT NULL,
+	prev_count DOUBLE PRECISION NOT NULL,
+	timestamp  TIMESTAMPTZ NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+)`)
+	return err
+}
+
+func (s *sqlStore) update(ctx context.Context, key string, ttl time.Duration,
+	fn func(current state, found bool) state) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	// inserts the row if it does not exist, or locks it until the transaction ends, returning its current state
+	now := s.now()
+	var (
+		current   state
+		expiresAt time.Time
+	)
+	err = tx.QueryRowContext(ctx, `INSERT INTO `+s.table+` AS t (key, count, prev_count, timestamp, expires_at)
+VALUES ($1, 0, 0, $2, $2) ON CONFLICT (key) DO UPDATE SET expires_at = t.expires_at
+RETURNING t.count, t.prev_count, t.timestamp, t.expires_at`, key, now).
+		Scan(&current.Count, &current.PrevCount, &current.Timestamp, &expiresAt)
+	if err != nil {
+		return err
+	}
+
+	next := fn(current, now.Before(expiresAt))
+	_, err = tx.ExecContext(ctx, `UPDATE `+s.table+` SET count = $2, prev_count = $3, timestamp = $4,
+expires_at = $5 WHERE key = $1`, key, next.Count, next.PrevCount, next.Timestamp, now.Add(ttl))
+	return err
+}
+
+// removeExpired removes every expired row from the store.
+func (s *sqlStore) removeExpired(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE expires_at < $1`, s.now())
+	return err
+}
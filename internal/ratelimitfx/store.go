@@ -0,0 +1,60 @@
+package ratelimitfx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// -- In-memory --
+
+type memoryEntry struct {
+	state     state
+	expiresAt time.Time
+}
+
+// memoryStore is a [store] keeping state in process memory. State is not shared between application replicas.
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// compile-time assertion
+var _ store = (*memoryStore)(nil)
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *memoryStore) update(_ context.Context, key string, ttl time.Duration,
+	fn func(current state, found bool) state) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	entry, found := s.entries[key]
+	if found && now.After(entry.expiresAt) {
+		found = false
+	}
+	s.entries[key] = memoryEntry{
+		state:     fn(entry.state, found),
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
+
+// removeExpired removes every expired entry from the store.
+func (s *memoryStore) removeExpired(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package adminfx
+
+import "time"
+
+type config struct {
+	Address           string        `env:"ADMIN_ADDRESS" validate:"omitempty,hostname_port"`
+	Token             string        `env:"ADMIN_TOKEN" validate:"omitempty,min=16" secret:"true"`
+	ReadHeaderTimeout time.Duration `env:"ADMIN_READ_HEADER_TIMEOUT" envDefault:"10s" validate:"gt=0"`
+}
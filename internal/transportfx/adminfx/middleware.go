@@ -0,0 +1,25 @@
+package adminfx
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/enclave/authn"
+)
+
+// newTokenMiddleware requires `token` as bearer token. Every request is rejected if `token` is empty, so
+// protected endpoints are never exposed unauthenticated.
+func newTokenMiddleware(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			reqToken, found := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if token == "" || !found || subtle.ConstantTimeCompare([]byte(reqToken), []byte(token)) != 1 {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return authn.ErrUnauthenticated
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package adminfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+// Module is the `uber/fx` module of the admin HTTP server, serving operational endpoints (metrics, profiling, log
+// level, etc.) on a dedicated listener, separated from the public HTTP server.
+//
+// The server listens on `ADMIN_ADDRESS` (e.g. `localhost:6060`) and is disabled if unset. Endpoints are registered
+// by every [Controller] in the `admin_controllers` group; protected ones require `ADMIN_TOKEN` as bearer token and
+// are rejected if no token is set.
+var Module = fx.Module("enclave/transport/admin",
+	fx.Provide(
+		osenv.ParseAs[config],
+		newServer,
+	),
+	fx.Invoke(
+		registerEndpoints,
+		startServer,
+	),
+)
+
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
+// Controller registers endpoints on the admin server.
+type Controller interface {
+	// SetAdminEndpoints registers public endpoints on `public` and endpoints requiring the admin token on
+	// `protected`.
+	SetAdminEndpoints(public, protected *echo.Group)
+}
+
+// Server is the admin HTTP server.
+type Server struct {
+	cfg  config
+	echo *echo.Echo
+}
+
+// Enabled indicates whether the admin server is listening (i.e. `ADMIN_ADDRESS` is set). Nil-safe, so modules may
+// depend on an optional [Server].
+func (s *Server) Enabled() bool {
+	return s != nil && s.cfg.Address != ""
+}
+
+// TokenMiddleware returns a middleware requiring `ADMIN_TOKEN` as bearer token, protecting admin endpoints served
+// by other servers (e.g. the HTTP server if the admin server is disabled). Every request is rejected if no token is
+// set. Nil-safe.
+func (s *Server) TokenMiddleware() echo.MiddlewareFunc {
+	if s == nil {
+		return newTokenMiddleware("")
+	}
+	return newTokenMiddleware(s.cfg.Token)
+}
+
+// -- Factory --
+
+func newServer(cfg config) *Server {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = geckhttp.NewErrorHandler("json")
+	e.Server.ReadHeaderTimeout = cfg.ReadHeaderTimeout
+	return &Server{
+		cfg:  cfg,
+		echo: e,
+	}
+}
+
+// -- Invoker --
+
+type registerEndpointsDeps struct {
+	fx.In
+	Server      *Server
+	Controllers []Controller `group:"admin_controllers"`
+}
+
+func registerEndpoints(deps registerEndpointsDeps) {
+	if !deps.Server.Enabled() {
+		return
+	}
+	public := deps.Server.echo.Group("")
+	protected := deps.Server.echo.Group("", deps.Server.TokenMiddleware())
+	for _, controller := range deps.Controllers {
+		controller.SetAdminEndpoints(public, protected)
+	}
+}
+
+// exitCodeServerFailure is the exit code used when the admin server fails after the application started.
+const exitCodeServerFailure = 1
+
+func startServer(lc fx.Lifecycle, shutdowner fx.Shutdowner, server *Server) {
+	if !server.Enabled() {
+		return
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := new(net.ListenConfig).Listen(ctx, "tcp", server.cfg.Address)
+			if err != nil {
+				return fmt.Errorf("enclave.admin: failed to listen on %s: %w", server.cfg.Address, err)
+			}
+			globallog.Logger().InfoContext(ctx, "starting admin server",
+				slog.String("addr", listener.Addr().String()),
+			)
+			go serve(shutdowner, server, listener)
+			return nil
+		},
+		OnStop: server.echo.Shutdown,
+	})
+}
+
+// serve runs `server` on `listener` until it is closed. Any other error stops the application with a non-zero
+// exit code.
+func serve(shutdowner fx.Shutdowner, server *Server, listener net.Listener) {
+	err := server.echo.Server.Serve(listener)
+	if err == nil || errors.Is(err, http.ErrServerClosed) {
+		return
+	}
+	globallog.Logger().Error("failed during admin server execution, stopping application",
+		slog.String("error", err.Error()),
+	)
+	if errShutdown := shutdowner.Shutdown(fx.ExitCode(exitCodeServerFailure)); errShutdown != nil {
+		globallog.Logger().Error("failed to stop application",
+			slog.String("error", errShutdown.Error()),
+		)
+	}
+}
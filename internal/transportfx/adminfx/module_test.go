@@ -0,0 +1,80 @@
+package adminfx
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type shutdownerStub struct {
+	calls int
+}
+
+func (s *shutdownerStub) Shutdown(_ ...fx.ShutdownOption) error {
+	s.calls++
+	return nil
+}
+
+type controllerStub struct{}
+
+func (controllerStub) SetAdminEndpoints(public, protected *echo.Group) {
+	public.GET("/metrics", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	protected.GET("/debug/pprof/heap", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+}
+
+func TestServer(t *testing.T) {
+	newRequest := func(server *Server, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		server.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	server := newServer(config{Address: "127.0.0.1:0", Token: "0123456789abcdef"})
+	registerEndpoints(registerEndpointsDeps{Server: server, Controllers: []Controller{controllerStub{}}})
+	assert.Equal(t, http.StatusOK, newRequest(server, "/metrics", "").Code)
+	rec := newRequest(server, "/debug/pprof/heap", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+	assert.Equal(t, http.StatusUnauthorized, newRequest(server, "/debug/pprof/heap", "wrong-token").Code)
+	assert.Equal(t, http.StatusOK, newRequest(server, "/debug/pprof/heap", "0123456789abcdef").Code)
+	shutdowner := &shutdownerStub{}
+	lc := fxtest.NewLifecycle(t)
+	startServer(lc, shutdowner, server)
+	lc.RequireStart().RequireStop()
+	assert.Zero(t, shutdowner.calls)
+
+	// Serve failure stops the application
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+	serve(shutdowner, newServer(config{Address: "127.0.0.1:0"}), listener)
+	assert.Equal(t, 1, shutdowner.calls)
+
+	// Protected endpoints rejected without token
+	server = newServer(config{Address: "127.0.0.1:0"})
+	registerEndpoints(registerEndpointsDeps{Server: server, Controllers: []Controller{controllerStub{}}})
+	assert.Equal(t, http.StatusOK, newRequest(server, "/metrics", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, newRequest(server, "/debug/pprof/heap", "").Code)
+
+	// Disabled
+	server = newServer(config{})
+	assert.False(t, server.Enabled())
+	assert.False(t, (*Server)(nil).Enabled())
+	registerEndpoints(registerEndpointsDeps{Server: server, Controllers: []Controller{controllerStub{}}})
+	assert.Empty(t, server.echo.Routes())
+}
@@ -0,0 +1,57 @@
+package httpfx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// configureAutoTLS sets up `e` ACME certificate manager based on `cfg`.
+//
+// Certificates are only issued for hosts listed in `HTTP_SERVER_AUTO_TLS_HOSTS`. If `cache` is nil,
+// certificates are stored in `HTTP_SERVER_AUTO_TLS_CACHE_DIR`.
+func configureAutoTLS(e *echo.Echo, cfg serverConfig, cache autocert.Cache) error {
+	if len(cfg.AutoTLSHosts) == 0 {
+		return errors.New("enclave.http: auto TLS requires at least one allowed host (HTTP_SERVER_AUTO_TLS_HOSTS)")
+	}
+	if cache == nil {
+		cache = autocert.DirCache(cfg.AutoTLSCacheDir)
+	}
+	e.AutoTLSManager.Prompt = autocert.AcceptTOS
+	e.AutoTLSManager.Cache = cache
+	e.AutoTLSManager.HostPolicy = autocert.HostWhitelist(cfg.AutoTLSHosts...)
+	e.AutoTLSManager.Email = cfg.AutoTLSEmail
+	if cfg.AutoTLSDirectoryURL == "" {
+		return nil
+	}
+
+	client := &acme.Client{
+		DirectoryURL: cfg.AutoTLSDirectoryURL,
+	}
+	if cfg.AutoTLSDirectoryCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.AutoTLSDirectoryCAFile)
+		if err != nil {
+			return err
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caPEM) {
+			return errors.New("enclave.http: no valid certificates found in ACME directory CA bundle")
+		}
+		client.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs:    caPool,
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+		}
+	}
+	e.AutoTLSManager.Client = client
+	return nil
+}
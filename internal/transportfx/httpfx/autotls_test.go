@@ -0,0 +1,39 @@
+package httpfx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestConfigureAutoTLS(t *testing.T) {
+	// No allowed hosts
+	e := echo.New()
+	err := configureAutoTLS(e, serverConfig{AutoTLSCacheDir: t.TempDir()}, nil)
+	assert.Error(t, err)
+
+	// Allowed hosts with default cache
+	cacheDir := t.TempDir()
+	err = configureAutoTLS(e, serverConfig{
+		AutoTLSCacheDir:     cacheDir,
+		AutoTLSHosts:        []string{"api.example.com"},
+		AutoTLSEmail:        "ops@example.com",
+		AutoTLSDirectoryURL: "https://localhost:14000/dir",
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, autocert.DirCache(cacheDir), e.AutoTLSManager.Cache)
+	assert.Equal(t, "ops@example.com", e.AutoTLSManager.Email)
+	assert.Equal(t, "https://localhost:14000/dir", e.AutoTLSManager.Client.DirectoryURL)
+	assert.NoError(t, e.AutoTLSManager.HostPolicy(context.Background(), "api.example.com"))
+	assert.Error(t, e.AutoTLSManager.HostPolicy(context.Background(), "evil.example.com"))
+
+	// Custom cache
+	cache := autocert.DirCache(t.TempDir())
+	err = configureAutoTLS(e, serverConfig{AutoTLSHosts: []string{"api.example.com"}}, cache)
+	require.NoError(t, err)
+	assert.Equal(t, cache, e.AutoTLSManager.Cache)
+}
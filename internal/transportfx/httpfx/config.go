@@ -1,9 +1,80 @@
 package httpfx
 
+import (
+	"log/slog"
+	"time"
+)
+
 type serverConfig struct {
 	Address          string `env:"HTTP_SERVER_ADDRESS" envDefault:":8080"`
 	ErrResponseCodec string `env:"HTTP_SERVER_ERR_RESP_CODEC" envDefault:"json" validate:"omitempty,oneof=json xml text"`
 
-	EnableTLS     bool `env:"HTTP_SERVER_ENABLE_TLS"`
-	EnableAutoTLS bool `env:"HTTP_SERVER_ENABLE_AUTO_TLS"`
+	ReadTimeout        time.Duration `env:"HTTP_SERVER_READ_TIMEOUT" envDefault:"30s" validate:"gte=0"`
+	ReadHeaderTimeout  time.Duration `env:"HTTP_SERVER_READ_HEADER_TIMEOUT" envDefault:"10s" validate:"gte=0"`
+	WriteTimeout       time.Duration `env:"HTTP_SERVER_WRITE_TIMEOUT" validate:"gte=0"`
+	IdleTimeout        time.Duration `env:"HTTP_SERVER_IDLE_TIMEOUT" envDefault:"120s" validate:"gte=0"`
+	MaxHeaderBytes     int           `env:"HTTP_SERVER_MAX_HEADER_BYTES" envDefault:"1048576" validate:"gte=0"`
+	MaxRequestBodySize string        `env:"HTTP_SERVER_MAX_REQUEST_BODY_SIZE"`
+	TrustedProxies     []string      `env:"HTTP_SERVER_TRUSTED_PROXIES" validate:"dive,cidr"`
+
+	EnableRequestID    bool       `env:"HTTP_SERVER_ENABLE_REQUEST_ID" envDefault:"true"`
+	RequestIDHeader    string     `env:"HTTP_SERVER_REQUEST_ID_HEADER" envDefault:"X-Request-ID" validate:"required"`
+	EnableAccessLog    bool       `env:"HTTP_SERVER_ENABLE_ACCESS_LOG" envDefault:"true"`
+	AccessLogLevel     slog.Level `env:"HTTP_SERVER_ACCESS_LOG_LEVEL" envDefault:"INFO"`
+	AccessLogSkipPaths []string   `env:"HTTP_SERVER_ACCESS_LOG_SKIP_PATHS"`
+	EnableRecovery     bool       `env:"HTTP_SERVER_ENABLE_RECOVERY" envDefault:"true"`
+	EnableGzip         bool       `env:"HTTP_SERVER_ENABLE_GZIP" envDefault:"true"`
+
+	ShutdownDrainDelay time.Duration `env:"HTTP_SERVER_SHUTDOWN_DRAIN_DELAY" validate:"gte=0"`
+	ShutdownTimeout    time.Duration `env:"HTTP_SERVER_SHUTDOWN_TIMEOUT" envDefault:"10s" validate:"gt=0"`
+
+	EnableTLS         bool          `env:"HTTP_SERVER_ENABLE_TLS"`
+	EnableAutoTLS     bool          `env:"HTTP_SERVER_ENABLE_AUTO_TLS"`
+	TLSCertFile       string        `env:"HTTP_SERVER_TLS_CERT_FILE"`
+	TLSKeyFile        string        `env:"HTTP_SERVER_TLS_KEY_FILE"`
+	TLSCert           string        `env:"HTTP_SERVER_TLS_CERT"`
+	TLSKey            string        `env:"HTTP_SERVER_TLS_KEY" secret:"true"`
+	TLSClientCAFile   string        `env:"HTTP_SERVER_TLS_CLIENT_CA_FILE"`
+	TLSClientCA       string        `env:"HTTP_SERVER_TLS_CLIENT_CA"`
+	TLSClientAuth     string        `env:"HTTP_SERVER_TLS_CLIENT_AUTH" validate:"omitempty,oneof=none request require verify_if_given require_and_verify"`
+	TLSMinVersion     string        `env:"HTTP_SERVER_TLS_MIN_VERSION" envDefault:"1.2" validate:"required,oneof=1.0 1.1 1.2 1.3"`
+	TLSCipherSuites   []string      `env:"HTTP_SERVER_TLS_CIPHER_SUITES"`
+	TLSReloadInterval time.Duration `env:"HTTP_SERVER_TLS_RELOAD_INTERVAL" envDefault:"1m" validate:"gte=0"`
+
+	AutoTLSCacheDir        string   `env:"HTTP_SERVER_AUTO_TLS_CACHE_DIR" envDefault:"/var/www/.cache"`
+	AutoTLSHosts           []string `env:"HTTP_SERVER_AUTO_TLS_HOSTS" validate:"dive,hostname"`
+	AutoTLSEmail           string   `env:"HTTP_SERVER_AUTO_TLS_EMAIL" validate:"omitempty,email"`
+	AutoTLSDirectoryURL    string   `env:"HTTP_SERVER_AUTO_TLS_DIRECTORY_URL" validate:"omitempty,url"`
+	AutoTLSDirectoryCAFile string   `env:"HTTP_SERVER_AUTO_TLS_DIRECTORY_CA_FILE"`
+}
+
+type corsConfig struct {
+	Enable           bool          `env:"HTTP_SERVER_ENABLE_CORS"`
+	AllowedOrigins   []string      `env:"HTTP_SERVER_CORS_ALLOWED_ORIGINS" validate:"required_if=Enable true"`
+	AllowedMethods   []string      `env:"HTTP_SERVER_CORS_ALLOWED_METHODS" envDefault:"GET,HEAD,PUT,PATCH,POST,DELETE"`
+	AllowedHeaders   []string      `env:"HTTP_SERVER_CORS_ALLOWED_HEADERS"`
+	ExposedHeaders   []string      `env:"HTTP_SERVER_CORS_EXPOSED_HEADERS"`
+	AllowCredentials bool          `env:"HTTP_SERVER_CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `env:"HTTP_SERVER_CORS_MAX_AGE" validate:"gte=0"`
+}
+
+type securityHeadersConfig struct {
+	Enable                bool          `env:"HTTP_SERVER_ENABLE_SECURITY_HEADERS"`
+	HSTSMaxAge            time.Duration `env:"HTTP_SERVER_HSTS_MAX_AGE" envDefault:"8760h" validate:"gte=0"`
+	HSTSIncludeSubdomains bool          `env:"HTTP_SERVER_HSTS_INCLUDE_SUBDOMAINS" envDefault:"true"`
+	HSTSPreload           bool          `env:"HTTP_SERVER_HSTS_PRELOAD"`
+	ContentSecurityPolicy string        `env:"HTTP_SERVER_CONTENT_SECURITY_POLICY"`
+	FrameOptions          string        `env:"HTTP_SERVER_FRAME_OPTIONS" envDefault:"DENY" validate:"omitempty,oneof=DENY SAMEORIGIN"`
+	ReferrerPolicy        string        `env:"HTTP_SERVER_REFERRER_POLICY" envDefault:"strict-origin-when-cross-origin"`
+}
+
+type csrfConfig struct {
+	Enable       bool          `env:"HTTP_SERVER_ENABLE_CSRF"`
+	TokenLookup  string        `env:"HTTP_SERVER_CSRF_TOKEN_LOOKUP" envDefault:"header:X-CSRF-Token" validate:"required"`
+	CookieName   string        `env:"HTTP_SERVER_CSRF_COOKIE_NAME" envDefault:"_csrf" validate:"required"`
+	CookieDomain string        `env:"HTTP_SERVER_CSRF_COOKIE_DOMAIN"`
+	CookiePath   string        `env:"HTTP_SERVER_CSRF_COOKIE_PATH" envDefault:"/"`
+	CookieMaxAge time.Duration `env:"HTTP_SERVER_CSRF_COOKIE_MAX_AGE" envDefault:"24h" validate:"gte=0"`
+	CookieSecure bool          `env:"HTTP_SERVER_CSRF_COOKIE_SECURE" envDefault:"true"`
+	SkipPaths    []string      `env:"HTTP_SERVER_CSRF_SKIP_PATHS"`
 }
@@ -0,0 +1,46 @@
+package httpfx
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/enclave/internal/observabilityfx/errorreportingfx"
+)
+
+// registerErrorReporting decorates the HTTP server error handler, reporting errors committed as 5xx responses
+// (including recovered panics) with `reporter`. Panics are recovered even if `HTTP_SERVER_ENABLE_RECOVERY` is
+// disabled, so they are reported. No-op if `reporter` is nil (i.e. the error reporting module is absent).
+// Errors are reported once, even if middlewares pass them through the error handler more than once.
+func registerErrorReporting(e *echo.Echo, cfg serverConfig, reporter *errorreportingfx.Reporter) {
+	if reporter == nil {
+		return
+	}
+	if !cfg.EnableRecovery {
+		e.Use(newRecoveryMiddleware())
+	}
+	next := e.HTTPErrorHandler
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		committed := c.Response().Committed
+		next(err, c)
+		status := c.Response().Status
+		if committed || status < http.StatusInternalServerError {
+			return
+		}
+		req := c.Request()
+		opts := []errorreportingfx.ReportOption{
+			errorreportingfx.WithRequest(req),
+			errorreportingfx.WithTags(map[string]string{
+				"http.method": req.Method,
+				"http.route":  c.Path(),
+				"http.status": strconv.Itoa(status),
+			}),
+		}
+		if errors.As(err, new(panicError)) {
+			opts = append(opts, errorreportingfx.WithFatalLevel())
+		}
+		reporter.Report(req.Context(), err, opts...)
+	}
+}
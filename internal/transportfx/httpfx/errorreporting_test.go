@@ -0,0 +1,80 @@
+package httpfx
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bosonicalio/geck/application"
+	"github.com/bosonicalio/geck/environment"
+	"github.com/bosonicalio/geck/syserr"
+	"github.com/bosonicalio/geck/version"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/bosonicalio/enclave/internal/observabilityfx/errorreportingfx"
+	"github.com/bosonicalio/enclave/internal/observabilityfx/errorreportingfx/errorreportingtest"
+)
+
+func TestRegisterErrorReporting(t *testing.T) {
+	standIn := errorreportingtest.NewSentryStandIn(t)
+	t.Setenv("ERROR_REPORTING_DSN", standIn.DSN())
+	var reporter *errorreportingfx.Reporter
+	app := fxtest.New(t,
+		fx.Supply(application.Application{
+			Name:        "orders",
+			Version:     version.MustParse("v1.2.3"),
+			Environment: environment.Production,
+		}),
+		errorreportingfx.Module,
+		fx.Populate(&reporter),
+	)
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	// recovered even if disabled
+	cfg := serverConfig{ErrResponseCodec: "json", EnableAccessLog: true}
+	e, err := newServer(cfg, newRequestTracker(), slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil)
+	require.NoError(t, err)
+	registerErrorReporting(e, cfg, reporter)
+	e.GET("/panic", func(_ echo.Context) error {
+		panic("boom")
+	})
+	e.GET("/internal", func(_ echo.Context) error {
+		return errors.New("database is down")
+	})
+	e.GET("/missing", func(_ echo.Context) error {
+		return syserr.New(syserr.ResourceNotFound, "order not found")
+	})
+	serve := func(path string) []string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.True(t, reporter.Flush())
+		return standIn.Received()
+	}
+
+	// Panic reported as fatal, with the stack trace of the panicking handler
+	envelopes := serve("/panic")
+	require.Len(t, envelopes, 1)
+	assert.Contains(t, envelopes[0], `"boom"`)
+	assert.Contains(t, envelopes[0], `"level":"fatal"`)
+	assert.Contains(t, envelopes[0], `"http.status":"500"`)
+	assert.Contains(t, envelopes[0], `"type":"httpfx.panicError"`)
+	assert.Contains(t, envelopes[0], `"function":"TestRegisterErrorReporting.func1"`)
+
+	// 5xx reported once, even though the access log middleware propagates the error
+	envelopes = serve("/internal")
+	require.Len(t, envelopes, 2)
+	assert.Contains(t, envelopes[1], `"database is down"`)
+	assert.Contains(t, envelopes[1], `"level":"error"`)
+	assert.Contains(t, envelopes[1], `"http.route":"/internal"`)
+
+	// 4xx not reported
+	assert.Len(t, serve("/missing"), 2)
+}
@@ -1,75 +1,187 @@
 package httpfx
 
 import (
+	"cmp"
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"slices"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/fx"
 	"golang.org/x/crypto/acme/autocert"
 
 	"github.com/bosonicalio/geck/application"
 	geckhttp "github.com/bosonicalio/geck/transport/http"
 
+	enclavehttp "github.com/bosonicalio/enclave/http"
 	"github.com/bosonicalio/enclave/internal/globallog"
+	"github.com/bosonicalio/enclave/internal/healthfx"
 )
 
+// exitCodeServerFailure is the exit code used when the HTTP server fails after the application started.
+const exitCodeServerFailure = 1
+
 type startServerDeps struct {
 	fx.In
 
-	Lifecycle fx.Lifecycle
-	Echo      *echo.Echo
-	Config    serverConfig
+	Lifecycle     fx.Lifecycle
+	Shutdowner    fx.Shutdowner
+	Echo          *echo.Echo
+	Config        serverConfig
+	Tracker       *requestTracker
+	AutocertCache autocert.Cache    `optional:"true"`
+	Health        *healthfx.Monitor `optional:"true"`
 }
 
 func startServer(deps startServerDeps) {
+	var tlsCertReloader *tlsReloader
 	deps.Lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			go func() {
-				globallog.Logger().InfoContext(ctx, "starting http server",
-					slog.String("addr", deps.Config.Address),
-				)
+			var tlsConfig *tls.Config
+			if deps.Config.EnableTLS {
 				var err error
-				if deps.Config.EnableTLS {
-					// TODO: use TLS configuration from the config
-					err = deps.Echo.StartTLS(deps.Config.Address, "", "")
-				} else if deps.Config.EnableAutoTLS {
-					deps.Echo.AutoTLSManager.Cache = autocert.DirCache("/var/www/.cache")
-					err = deps.Echo.StartAutoTLS(deps.Config.Address)
-				} else {
-					err = deps.Echo.Start(deps.Config.Address)
+				tlsConfig, tlsCertReloader, err = newTLSConfig(deps.Config)
+				if err != nil {
+					return err
 				}
-				if errors.Is(err, http.ErrServerClosed) {
-					return
-				} else if err != nil {
-					globallog.Logger().ErrorContext(ctx, "failed during http server execution",
-						slog.String("error", err.Error()),
-					)
+			} else if deps.Config.EnableAutoTLS {
+				if err := configureAutoTLS(deps.Echo, deps.Config, deps.AutocertCache); err != nil {
+					return err
 				}
-			}()
+				tlsConfig = deps.Echo.AutoTLSManager.TLSConfig()
+			}
+
+			// bind synchronously so errors like "address already in use" fail the application startup
+			listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", deps.Config.Address)
+			if err != nil {
+				return fmt.Errorf("enclave.http: failed to listen on %s: %w", deps.Config.Address, err)
+			}
+			server := deps.Echo.Server
+			if tlsConfig != nil {
+				server = deps.Echo.TLSServer
+				server.TLSConfig = tlsConfig
+				deps.Echo.TLSListener = tls.NewListener(listener, tlsConfig)
+			} else {
+				deps.Echo.Listener = listener
+			}
+			server.Addr = deps.Config.Address
+			if tlsCertReloader != nil {
+				// watched once bound only, so a failed startup does not leak the watcher
+				go tlsCertReloader.watch(deps.Config.TLSReloadInterval)
+			}
+
+			globallog.Logger().InfoContext(ctx, "starting http server",
+				slog.String("addr", listener.Addr().String()),
+				slog.Bool("tls", tlsConfig != nil),
+			)
+			go serve(deps, server)
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			globallog.Logger().InfoContext(ctx, "stopping http server")
-			return deps.Echo.Shutdown(ctx)
+			if tlsCertReloader != nil {
+				defer tlsCertReloader.close()
+			}
+			return shutdownServer(ctx, deps)
 		},
 	})
 }
 
+// serve runs `server` until it is closed. Any other error stops the application with a non-zero exit code.
+func serve(deps startServerDeps, server *http.Server) {
+	err := deps.Echo.StartServer(server)
+	if err == nil || errors.Is(err, http.ErrServerClosed) {
+		return
+	}
+	globallog.Logger().Error("failed during http server execution, stopping application",
+		slog.String("error", err.Error()),
+	)
+	if errShutdown := deps.Shutdowner.Shutdown(fx.ExitCode(exitCodeServerFailure)); errShutdown != nil {
+		globallog.Logger().Error("failed to stop application",
+			slog.String("error", errShutdown.Error()),
+		)
+	}
+}
+
+func registerSecurityMiddlewares(e *echo.Echo, corsCfg corsConfig, headersCfg securityHeadersConfig,
+	csrfCfg csrfConfig) {
+	if corsCfg.Enable {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins:     corsCfg.AllowedOrigins,
+			AllowMethods:     corsCfg.AllowedMethods,
+			AllowHeaders:     corsCfg.AllowedHeaders,
+			ExposeHeaders:    corsCfg.ExposedHeaders,
+			AllowCredentials: corsCfg.AllowCredentials,
+			MaxAge:           int(corsCfg.MaxAge.Seconds()),
+		}))
+	}
+	if headersCfg.Enable {
+		// HSTS header is only written by the middleware for TLS requests
+		e.Use(middleware.SecureWithConfig(middleware.SecureConfig{
+			XSSProtection:         "0",
+			ContentTypeNosniff:    "nosniff",
+			XFrameOptions:         headersCfg.FrameOptions,
+			HSTSMaxAge:            int(headersCfg.HSTSMaxAge.Seconds()),
+			HSTSExcludeSubdomains: !headersCfg.HSTSIncludeSubdomains,
+			HSTSPreloadEnabled:    headersCfg.HSTSPreload,
+			ContentSecurityPolicy: headersCfg.ContentSecurityPolicy,
+			ReferrerPolicy:        headersCfg.ReferrerPolicy,
+		}))
+	}
+	if csrfCfg.Enable {
+		e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{
+			Skipper: func(c echo.Context) bool {
+				return slices.Contains(csrfCfg.SkipPaths, c.Request().URL.Path)
+			},
+			TokenLookup:    csrfCfg.TokenLookup,
+			CookieName:     csrfCfg.CookieName,
+			CookieDomain:   csrfCfg.CookieDomain,
+			CookiePath:     csrfCfg.CookiePath,
+			CookieMaxAge:   int(csrfCfg.CookieMaxAge.Seconds()),
+			CookieSecure:   csrfCfg.CookieSecure,
+			CookieHTTPOnly: true,
+			CookieSameSite: http.SameSiteStrictMode,
+		}))
+	}
+}
+
 type registerServerEndpointsDeps struct {
 	fx.In
 	Echo        *echo.Echo
 	Logger      *slog.Logger
 	App         application.Application
-	Controllers []geckhttp.Controller `group:"http_controllers"`
+	Controllers []geckhttp.Controller    `group:"http_controllers"`
+	Middlewares []enclavehttp.Middleware `group:"http_middlewares"`
 }
 
 func registerServerEndpoints(deps registerServerEndpointsDeps) {
-	pathPrefix := geckhttp.RegisterServerEndpoints(deps.Echo, deps.App, deps.Controllers)
+	middlewares := slices.Clone(deps.Middlewares)
+	slices.SortStableFunc(middlewares, func(a, b enclavehttp.Middleware) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+	for _, middleware := range middlewares {
+		deps.Echo.Use(middleware.Func)
+	}
+
+	// Based on [geckhttp.RegisterServerEndpoints], scoping controller middlewares to their own route group.
+	pathPrefix := "/" + deps.App.Version.Major()
+	versionedGroup := deps.Echo.Group(pathPrefix)
+	for _, controller := range deps.Controllers {
+		controller.SetEndpoints(deps.Echo)
+		if mwController, ok := controller.(enclavehttp.MiddlewareController); ok {
+			controller.SetVersionedEndpoints(versionedGroup.Group("", mwController.Middlewares()...))
+			continue
+		}
+		controller.SetVersionedEndpoints(versionedGroup)
+	}
 	globallog.Logger().Debug("registered http server endpoints",
 		slog.String("path_prefix", pathPrefix),
 		slog.Int("total_controllers", len(deps.Controllers)),
+		slog.Int("total_middlewares", len(middlewares)),
 	)
 }
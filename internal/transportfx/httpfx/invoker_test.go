@@ -0,0 +1,180 @@
+package httpfx
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/bosonicalio/geck/application"
+	geckhttp "github.com/bosonicalio/geck/transport/http"
+	"github.com/bosonicalio/geck/version"
+
+	enclavehttp "github.com/bosonicalio/enclave/http"
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+type shutdownerStub struct {
+	calls int
+}
+
+func (s *shutdownerStub) Shutdown(_ ...fx.ShutdownOption) error {
+	s.calls++
+	return nil
+}
+
+func TestStartServer(t *testing.T) {
+	busyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busyListener.Close()
+
+	// Address already in use
+	tracker := newRequestTracker()
+	e, err := newServer(serverConfig{}, tracker, slog.Default(), nil, nil)
+	require.NoError(t, err)
+	lc := fxtest.NewLifecycle(t)
+	startServer(startServerDeps{
+		Lifecycle:  lc,
+		Shutdowner: &shutdownerStub{},
+		Echo:       e,
+		Config:     serverConfig{Address: busyListener.Addr().String()},
+		Tracker:    tracker,
+	})
+	assert.Error(t, lc.Start(context.Background()))
+
+	// Free address
+	shutdowner := &shutdownerStub{}
+	lc = fxtest.NewLifecycle(t)
+	e, err = newServer(serverConfig{}, tracker, slog.Default(), nil, nil)
+	require.NoError(t, err)
+	startServer(startServerDeps{
+		Lifecycle:  lc,
+		Shutdowner: shutdowner,
+		Echo:       e,
+		Config:     serverConfig{Address: "127.0.0.1:0", ShutdownTimeout: time.Second},
+		Tracker:    tracker,
+	})
+	require.NoError(t, lc.Start(context.Background()))
+	assert.NotNil(t, e.ListenerAddr())
+	require.NoError(t, lc.Stop(context.Background()))
+	assert.Zero(t, shutdowner.calls)
+}
+
+type controllerStub struct {
+	middlewares []echo.MiddlewareFunc
+}
+
+func (c controllerStub) SetEndpoints(e *echo.Echo) {
+	e.GET("/ping", c.handle)
+}
+
+func (c controllerStub) SetVersionedEndpoints(g *echo.Group) {
+	g.GET("/items", c.handle)
+}
+
+func (c controllerStub) Middlewares() []echo.MiddlewareFunc {
+	return c.middlewares
+}
+
+func (c controllerStub) handle(ctx echo.Context) error {
+	return ctx.String(http.StatusOK, ctx.Response().Header().Get("X-Trace"))
+}
+
+func newTraceMiddleware(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Add("X-Trace", name)
+			return next(c)
+		}
+	}
+}
+
+func TestRegisterServerEndpoints(t *testing.T) {
+	ver, err := version.Parse("v1.2.0")
+	require.NoError(t, err)
+	e := echo.New()
+	registerServerEndpoints(registerServerEndpointsDeps{
+		Echo: e,
+		App:  application.Application{Version: ver},
+		Controllers: []geckhttp.Controller{
+			controllerStub{middlewares: []echo.MiddlewareFunc{newTraceMiddleware("controller")}},
+		},
+		Middlewares: []enclavehttp.Middleware{
+			{Func: newTraceMiddleware("third"), Priority: 10},
+			{Func: newTraceMiddleware("first"), Priority: -10},
+			{Func: newTraceMiddleware("second"), Priority: 0},
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/items", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"first", "second", "third", "controller"}, rec.Header().Values("X-Trace"))
+
+	// Controller middlewares only apply to versioned endpoints
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, []string{"first", "second", "third"}, rec.Header().Values("X-Trace"))
+}
+
+func TestRegisterSecurityMiddlewares(t *testing.T) {
+	// Defaults from environment
+	_, err := osenv.ParseAs[corsConfig]()
+	require.NoError(t, err)
+	headersCfg, err := osenv.ParseAs[securityHeadersConfig]()
+	require.NoError(t, err)
+	assert.False(t, headersCfg.Enable)
+	t.Setenv("HTTP_SERVER_ENABLE_CORS", "true")
+	_, err = osenv.ParseAs[corsConfig]()
+	assert.Error(t, err)
+
+	t.Setenv("HTTP_SERVER_CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	t.Setenv("HTTP_SERVER_ENABLE_SECURITY_HEADERS", "true")
+	t.Setenv("HTTP_SERVER_ENABLE_CSRF", "true")
+	t.Setenv("HTTP_SERVER_CSRF_SKIP_PATHS", "/webhooks")
+	corsCfg, err := osenv.ParseAs[corsConfig]()
+	require.NoError(t, err)
+	headersCfg, err = osenv.ParseAs[securityHeadersConfig]()
+	require.NoError(t, err)
+	csrfCfg, err := osenv.ParseAs[csrfConfig]()
+	require.NoError(t, err)
+
+	e := echo.New()
+	registerSecurityMiddlewares(e, corsCfg, headersCfg, csrfCfg)
+	handler := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.GET("/items", handler)
+	e.POST("/items", handler)
+	e.POST("/webhooks", handler)
+
+	// CORS preflight
+	req := httptest.NewRequest(http.MethodOptions, "/items", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	// Security headers
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "DENY", rec.Header().Get(echo.HeaderXFrameOptions))
+	assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+
+	// CSRF protection
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhooks", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
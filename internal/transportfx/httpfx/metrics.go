@@ -0,0 +1,59 @@
+package httpfx
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+const _meterName = "github.com/bosonicalio/enclave/internal/transportfx/httpfx"
+
+// newMetricsMiddleware records the duration and body sizes of every request, labelled with the request method,
+// route and response status code.
+func newMetricsMiddleware(mp metric.MeterProvider) (echo.MiddlewareFunc, error) {
+	meter := mp.Meter(_meterName)
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10),
+	)
+	if err != nil {
+		return nil, err
+	}
+	requestSize, err := meter.Int64Histogram("http.server.request.body.size",
+		metric.WithDescription("Size of HTTP server request bodies."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	responseSize, err := meter.Int64Histogram("http.server.response.body.size",
+		metric.WithDescription("Size of HTTP server response bodies."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			req, res := c.Request(), c.Response()
+			ctx := req.Context()
+			attrs := metric.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.HTTPRoute(c.Path()),
+				semconv.HTTPResponseStatusCode(res.Status),
+			)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+			if req.ContentLength >= 0 {
+				requestSize.Record(ctx, req.ContentLength, attrs)
+			}
+			responseSize.Record(ctx, res.Size, attrs)
+			return err
+		}
+	}, nil
+}
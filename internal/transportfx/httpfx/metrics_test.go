@@ -0,0 +1,62 @@
+package httpfx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestNewMetricsMiddleware(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	e, err := newServer(serverConfig{ErrResponseCodec: "json"}, newRequestTracker(), slog.Default(), nil, mp)
+	require.NoError(t, err)
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "order")
+	})
+	e.GET("/fail", func(_ echo.Context) error {
+		return errors.New("boom")
+	})
+	for range 3 {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	metrics := make(map[string]metricdata.Metrics)
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	require.Contains(t, metrics, "http.server.request.duration")
+	require.Contains(t, metrics, "http.server.response.body.size")
+	responseSize := metrics["http.server.response.body.size"].Data.(metricdata.Histogram[int64])
+	require.Len(t, responseSize.DataPoints, 2)
+	for _, point := range responseSize.DataPoints {
+		route, _ := point.Attributes.Value("http.route")
+		status, _ := point.Attributes.Value("http.response.status_code")
+		switch route.AsString() {
+		case "/orders/:id":
+			assert.Equal(t, uint64(3), point.Count)
+			assert.Equal(t, int64(15), point.Sum)
+			assert.Equal(t, int64(http.StatusOK), status.AsInt64())
+		case "/fail":
+			assert.Equal(t, uint64(1), point.Count)
+			assert.Equal(t, int64(http.StatusInternalServerError), status.AsInt64())
+		default:
+			assert.Fail(t, "unexpected route", route.AsString())
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package httpfx
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"slices"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	enclavehttp "github.com/bosonicalio/enclave/http"
+	"github.com/bosonicalio/enclave/logging"
+)
+
+const _maxRequestIDLength = 128
+
+// newContextLoggerMiddleware sets `logger` in every request context, so downstream components can enrich it
+// with request-scoped attributes (see [logging.FromContext]).
+func newContextLoggerMiddleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetRequest(c.Request().WithContext(logging.WithLogger(c.Request().Context(), logger)))
+			return next(c)
+		}
+	}
+}
+
+// newRequestIDMiddleware propagates the request identifier set by clients in the `header` request header,
+// generating a new one if missing or invalid.
+//
+// The identifier is written to the response headers, and set in the request context as correlation field
+// (see [logging.WithRequestID]), also bound to the context logger as the `request_id` attribute (see
+// [logging.FromContext]).
+func newRequestIDMiddleware(header string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(header)
+			if !isValidRequestID(id) {
+				id = uuid.NewString()
+			}
+			c.Response().Header().Set(header, id)
+
+			c.SetRequest(c.Request().WithContext(enclavehttp.WithRequestID(c.Request().Context(), id)))
+			return next(c)
+		}
+	}
+}
+
+// isValidRequestID prevents clients from injecting arbitrary data into logs through request identifiers.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > _maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// newErrorResponseMiddleware commits the response of errors returned by inner handlers through the HTTP server
+// error handler, so outer middlewares (e.g. access log, tracing and metrics) observe its status code. Errors are
+// still returned, while the error handler leaves committed responses untouched once they reach it.
+func newErrorResponseMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			return err
+		}
+	}
+}
+
+// newAccessLogMiddleware logs every request processed by the HTTP server with `level`, except those
+// targeting `skipPaths`.
+func newAccessLogMiddleware(level slog.Level, skipPaths []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(skipPaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			start := time.Now()
+			err := next(c)
+			req, res := c.Request(), c.Response()
+			ctx := req.Context()
+			lvl := level
+			if res.Status >= 500 {
+				lvl = slog.LevelError
+			}
+			logging.FromContext(ctx).LogAttrs(ctx, lvl, "processed http request",
+				slog.String("method", req.Method),
+				slog.String("uri", req.RequestURI),
+				slog.String("route", c.Path()),
+				slog.Int("status", res.Status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes_in", req.ContentLength),
+				slog.Int64("bytes_out", res.Size),
+				slog.String("remote_ip", c.RealIP()),
+				slog.String("user_agent", req.UserAgent()),
+			)
+			return err
+		}
+	}
+}
+
+// panicError is a panic recovered from a handler, keeping the program counters of the panicking goroutine so
+// error reporters are able to build its stack trace.
+type panicError struct {
+	err   error
+	stack []uintptr
+}
+
+func (e panicError) Error() string {
+	return e.err.Error()
+}
+
+func (e panicError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the program counters of the panicking goroutine.
+func (e panicError) StackTrace() []uintptr {
+	return e.stack
+}
+
+// newRecoveryMiddleware recovers panics from handlers, logging them with their stack trace and returning
+// an error (see [panicError]), so they are encoded by the HTTP server error handler.
+func newRecoveryMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+				errPanic, ok := r.(error)
+				if !ok {
+					errPanic = fmt.Errorf("%v", r)
+				}
+				stack := make([]byte, 4<<10)
+				stack = stack[:runtime.Stack(stack, false)]
+				ctx := c.Request().Context()
+				logging.FromContext(ctx).ErrorContext(ctx, "recovered from http handler panic",
+					slog.String("error", errPanic.Error()),
+					slog.String("stack", string(stack)),
+				)
+				pcs := make([]uintptr, 64)
+				err = panicError{
+					err:   errPanic,
+					stack: pcs[:runtime.Callers(3, pcs)],
+				}
+			}()
+			return next(c)
+		}
+	}
+}
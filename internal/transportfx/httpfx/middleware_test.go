@@ -0,0 +1,85 @@
+package httpfx
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	enclavehttp "github.com/bosonicalio/enclave/http"
+	"github.com/bosonicalio/enclave/logging"
+)
+
+func TestServerMiddlewares(t *testing.T) {
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(logs, nil))
+	e, err := newServer(serverConfig{
+		ErrResponseCodec: "json",
+		EnableRequestID:  true,
+		RequestIDHeader:  "X-Request-ID",
+		EnableAccessLog:  true,
+		AccessLogLevel:   slog.LevelInfo,
+		EnableRecovery:   true,
+	}, newRequestTracker(), logger, nil, nil)
+	require.NoError(t, err)
+	e.GET("/id", func(c echo.Context) error {
+		logging.FromContext(c.Request().Context()).Info("handling request")
+		return c.String(http.StatusOK, enclavehttp.RequestID(c.Request().Context()))
+	})
+	e.GET("/panic", func(_ echo.Context) error {
+		panic("boom")
+	})
+	var handledErr error
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			handledErr = next(c)
+			return handledErr
+		}
+	})
+
+	// Propagated request ID
+	req := httptest.NewRequest(http.MethodGet, "/id", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "abc-123", rec.Body.String())
+	assert.Equal(t, "abc-123", rec.Header().Get("X-Request-ID"))
+
+	var handlerLog, accessLog map[string]any
+	decoder := json.NewDecoder(logs)
+	require.NoError(t, decoder.Decode(&handlerLog))
+	require.NoError(t, decoder.Decode(&accessLog))
+	assert.Equal(t, "abc-123", handlerLog["request_id"])
+	assert.Equal(t, "processed http request", accessLog["msg"])
+	assert.Equal(t, "abc-123", accessLog["request_id"])
+	assert.Equal(t, float64(http.StatusOK), accessLog["status"])
+
+	// Generated request ID for invalid ones
+	req = httptest.NewRequest(http.MethodGet, "/id", nil)
+	req.Header.Set("X-Request-ID", "abc\n123")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.NotEqual(t, "abc\n123", rec.Body.String())
+	assert.NotEmpty(t, rec.Header().Get("X-Request-ID"))
+
+	// Recovered panic encoded with the error codec
+	logs.Reset()
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INTERNAL_SERVER_ERROR"`)
+	var panicLog map[string]any
+	decoder = json.NewDecoder(logs)
+	require.NoError(t, decoder.Decode(&panicLog))
+	assert.Equal(t, "recovered from http handler panic", panicLog["msg"])
+	assert.Equal(t, "boom", panicLog["error"])
+	require.NoError(t, decoder.Decode(&accessLog))
+	assert.Equal(t, float64(http.StatusInternalServerError), accessLog["status"])
+	assert.ErrorContains(t, handledErr, "boom")
+}
@@ -1,7 +1,16 @@
 package httpfx
 
 import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/bytes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 
 	geckhttp "github.com/bosonicalio/geck/transport/http"
@@ -12,21 +21,138 @@ import (
 // ServerModule is the `uber/fx` module of the [geckhttp] package, aimed for HTTP servers.
 //
 // This module uses `labstack/echo` as HTTP framework for internal operations.
+//
+// When TLS is enabled (`HTTP_SERVER_ENABLE_TLS`), certificates are loaded either from files or inline PEM values,
+// optionally verifying client certificates (mTLS). Certificate files are watched and reloaded on change, so
+// rotations do not require an application restart.
+//
+// Request ID propagation (`HTTP_SERVER_ENABLE_REQUEST_ID`), access logging (`HTTP_SERVER_ENABLE_ACCESS_LOG`),
+// panic recovery (`HTTP_SERVER_ENABLE_RECOVERY`) and gzip compression (`HTTP_SERVER_ENABLE_GZIP`) middlewares are
+// enabled by default, as installed by [geckhttp.NewEchoServer], and can be disabled individually. Requests are
+// traced and measured if the tracing and metrics modules are present, while panics and 5xx responses are reported
+// if the error reporting module is present.
+//
+// Client IPs (e.g. used by access logs and rate limiting) are taken from the connection, unless it comes from one of
+// `HTTP_SERVER_TRUSTED_PROXIES` (CIDR ranges), in which case they are taken from the `X-Forwarded-For` header.
+//
+// CORS (`HTTP_SERVER_ENABLE_CORS`), standard security headers (`HTTP_SERVER_ENABLE_SECURITY_HEADERS`: HSTS,
+// X-Frame-Options, CSP, etc., with secure defaults) and CSRF (`HTTP_SERVER_ENABLE_CSRF`) protections are opt-in, so
+// responses of existing services are unchanged.
+//
+// The underlying HTTP servers are configured with read, read header and idle timeouts and a header size limit
+// (`HTTP_SERVER_READ_TIMEOUT`, `HTTP_SERVER_MAX_HEADER_BYTES`, etc.), protecting them from slow or abusive clients.
+// Write timeouts (`HTTP_SERVER_WRITE_TIMEOUT`) and request body size limits (`HTTP_SERVER_MAX_REQUEST_BODY_SIZE`,
+// e.g. `4M`) are opt-in, as they cut off long-lived responses (e.g. server-sent events) and large uploads.
+//
+// On shutdown, the application is marked as not ready (if the health module is present) and the server keeps
+// serving requests during `HTTP_SERVER_SHUTDOWN_DRAIN_DELAY`, then waits up to `HTTP_SERVER_SHUTDOWN_TIMEOUT`
+// for in-flight requests to complete. Make sure the application stop timeout ([fx.StopTimeout]) is greater than
+// the sum of both values.
 var ServerModule = fx.Module("enclave/transport/http/server",
 	fx.Provide(
 		osenv.ParseAs[serverConfig],
-		newServer,
+		osenv.ParseAs[corsConfig],
+		osenv.ParseAs[securityHeadersConfig],
+		osenv.ParseAs[csrfConfig],
+		newRequestTracker,
+		fx.Annotate(
+			newServer,
+			fx.ParamTags("", "", "", `optional:"true"`, `optional:"true"`), // tracer and meter providers are optional
+		),
 	),
 	fx.Invoke(
+		registerSecurityMiddlewares,
 		registerServerEndpoints,
+		fx.Annotate(
+			registerErrorReporting,
+			fx.ParamTags("", "", `optional:"true"`), // reporter is optional
+		),
 		startServer,
 	),
 )
 
+// ServerConfigs are the configurations parsed by [ServerModule].
+var ServerConfigs = []osenv.ConfigSpec{
+	osenv.ConfigOf[serverConfig](""),
+	osenv.ConfigOf[corsConfig](""),
+	osenv.ConfigOf[securityHeadersConfig](""),
+	osenv.ConfigOf[csrfConfig](""),
+}
+
 // -- Factory --
 
-func newServer(cfg serverConfig) *echo.Echo {
-	return geckhttp.NewEchoServer(
-		geckhttp.WithServerErrorResponseCodec(cfg.ErrResponseCodec),
-	)
+// newServer allocates the HTTP server. Based on [geckhttp.NewEchoServer], replacing `labstack/echo` default
+// request ID, logging and recovery middlewares with configurable ones using the application logger.
+//
+// Requests are traced and measured if `tp` and `mp` are not nil (i.e. the tracing and metrics modules are present).
+func newServer(cfg serverConfig, tracker *requestTracker, logger *slog.Logger,
+	tp trace.TracerProvider, mp metric.MeterProvider) (*echo.Echo, error) {
+	e := echo.New()
+	e.HTTPErrorHandler = geckhttp.NewErrorHandler(cfg.ErrResponseCodec)
+	ipExtractor, err := newIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	e.IPExtractor = ipExtractor
+	for _, server := range []*http.Server{e.Server, e.TLSServer} {
+		server.ReadTimeout = cfg.ReadTimeout
+		server.ReadHeaderTimeout = cfg.ReadHeaderTimeout
+		server.WriteTimeout = cfg.WriteTimeout
+		server.IdleTimeout = cfg.IdleTimeout
+		server.MaxHeaderBytes = cfg.MaxHeaderBytes
+	}
+
+	if tp != nil {
+		e.Use(newTracingMiddleware(tp))
+	}
+	if mp != nil {
+		metricsMiddleware, err := newMetricsMiddleware(mp)
+		if err != nil {
+			return nil, err
+		}
+		e.Use(metricsMiddleware)
+	}
+	e.Use(newContextLoggerMiddleware(logger))
+	if cfg.EnableRequestID {
+		e.Use(newRequestIDMiddleware(cfg.RequestIDHeader))
+	}
+	if cfg.EnableAccessLog {
+		e.Use(newAccessLogMiddleware(cfg.AccessLogLevel, cfg.AccessLogSkipPaths))
+	}
+	e.Use(newErrorResponseMiddleware())
+	if cfg.EnableRecovery {
+		e.Use(newRecoveryMiddleware())
+	}
+	e.Use(tracker.Middleware)
+	if cfg.MaxRequestBodySize != "" {
+		if _, err := bytes.Parse(cfg.MaxRequestBodySize); err != nil {
+			return nil, fmt.Errorf("enclave.http: invalid max request body size: %w", err)
+		}
+		e.Use(middleware.BodyLimit(cfg.MaxRequestBodySize))
+	}
+	if cfg.EnableGzip {
+		e.Use(middleware.Gzip())
+	}
+	return e, nil
+}
+
+// newIPExtractor returns the [echo.IPExtractor] of client IPs. Forwarding headers are ignored unless
+// `trustedProxies` are set, so clients cannot spoof their IP.
+func newIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	opts := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("enclave.http: invalid trusted proxy: %w", err)
+		}
+		opts = append(opts, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(opts...), nil
 }
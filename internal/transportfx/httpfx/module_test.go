@@ -0,0 +1,68 @@
+package httpfx
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bosonicalio/enclave/internal/osenv"
+)
+
+func TestNewServer(t *testing.T) {
+	// Invalid body size
+	_, err := newServer(serverConfig{MaxRequestBodySize: "foo"}, newRequestTracker(), slog.Default(), nil, nil)
+	assert.Error(t, err)
+
+	// Limits from environment
+	t.Setenv("HTTP_SERVER_READ_TIMEOUT", "5s")
+	t.Setenv("HTTP_SERVER_MAX_REQUEST_BODY_SIZE", "1K")
+	cfg, err := osenv.ParseAs[serverConfig]()
+	require.NoError(t, err)
+	e, err := newServer(cfg, newRequestTracker(), slog.Default(), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, e.Server.ReadTimeout)
+	assert.Equal(t, 5*time.Second, e.TLSServer.ReadTimeout)
+	assert.Equal(t, 10*time.Second, e.Server.ReadHeaderTimeout)
+	assert.Equal(t, 1<<20, e.Server.MaxHeaderBytes)
+
+	e.POST("/echo", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("a", 2048)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Client IP
+	e.GET("/ip", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.RealIP())
+	})
+	newIPRequest := func(e *echo.Echo, remoteAddr string) string {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7")
+		req.Header.Set(echo.HeaderXRealIP, "203.0.113.8")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+	assert.Equal(t, "10.0.0.1", newIPRequest(e, "10.0.0.1:1234"))
+
+	_, err = newServer(serverConfig{TrustedProxies: []string{"foo"}}, newRequestTracker(), slog.Default(), nil, nil)
+	assert.Error(t, err)
+	e, err = newServer(serverConfig{TrustedProxies: []string{"10.0.0.0/8"}}, newRequestTracker(), slog.Default(),
+		nil, nil)
+	require.NoError(t, err)
+	e.GET("/ip", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.RealIP())
+	})
+	assert.Equal(t, "203.0.113.7", newIPRequest(e, "10.0.0.1:1234"))
+	assert.Equal(t, "192.168.0.1", newIPRequest(e, "192.168.0.1:1234"))
+}
@@ -0,0 +1,73 @@
+package httpfx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+)
+
+// requestTracker keeps count of the requests being processed by the HTTP server.
+type requestTracker struct {
+	inFlight atomic.Int64
+}
+
+func newRequestTracker() *requestTracker {
+	return &requestTracker{}
+}
+
+// Middleware is an [echo.MiddlewareFunc] tracking every request processed by the HTTP server.
+func (t *requestTracker) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		t.inFlight.Add(1)
+		defer t.inFlight.Add(-1)
+		return next(c)
+	}
+}
+
+// InFlight returns the number of requests being processed.
+func (t *requestTracker) InFlight() int64 {
+	return t.inFlight.Load()
+}
+
+// shutdownServer gracefully stops the HTTP server.
+//
+// The application is marked as not ready first, then the server keeps serving requests during the drain delay
+// so load balancers stop routing new traffic to it. Finally, the server is shut down waiting for in-flight
+// requests up to the shutdown timeout, closing remaining connections afterward.
+func shutdownServer(ctx context.Context, deps startServerDeps) error {
+	if deps.Health != nil {
+		deps.Health.SetReady(false)
+	}
+	if deps.Config.ShutdownDrainDelay > 0 {
+		globallog.Logger().InfoContext(ctx, "draining http server",
+			slog.Duration("drain_delay", deps.Config.ShutdownDrainDelay),
+			slog.Int64("in_flight_requests", deps.Tracker.InFlight()),
+		)
+		select {
+		case <-ctx.Done():
+		case <-time.After(deps.Config.ShutdownDrainDelay):
+		}
+	}
+
+	globallog.Logger().InfoContext(ctx, "stopping http server",
+		slog.Duration("timeout", deps.Config.ShutdownTimeout),
+		slog.Int64("in_flight_requests", deps.Tracker.InFlight()),
+	)
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deps.Config.ShutdownTimeout)
+	defer cancel()
+	if err := deps.Echo.Shutdown(shutdownCtx); err != nil {
+		globallog.Logger().WarnContext(ctx, "failed to gracefully stop http server, closing remaining connections",
+			slog.String("error", err.Error()),
+			slog.Int64("in_flight_requests", deps.Tracker.InFlight()),
+		)
+		return errors.Join(err, deps.Echo.Close())
+	}
+	globallog.Logger().InfoContext(ctx, "stopped http server")
+	return nil
+}
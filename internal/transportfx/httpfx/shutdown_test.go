@@ -0,0 +1,59 @@
+package httpfx
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bosonicalio/enclave/internal/healthfx"
+)
+
+func TestShutdownServer(t *testing.T) {
+	tracker := newRequestTracker()
+	e, err := newServer(serverConfig{ErrResponseCodec: "json"}, tracker, slog.Default(), nil, nil)
+	require.NoError(t, err)
+	e.HideBanner, e.HidePort = true, true
+	started := make(chan struct{})
+	e.GET("/slow", func(c echo.Context) error {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		return c.NoContent(http.StatusNoContent)
+	})
+	go func() { _ = e.Start("127.0.0.1:0") }()
+	require.Eventually(t, func() bool { return e.ListenerAddr() != nil }, time.Second, 10*time.Millisecond)
+
+	respCode := make(chan int, 1)
+	go func() {
+		res, errReq := http.Get("http://" + e.ListenerAddr().String() + "/slow")
+		if errReq != nil {
+			respCode <- 0
+			return
+		}
+		_ = res.Body.Close()
+		respCode <- res.StatusCode
+	}()
+	<-started
+	assert.Equal(t, int64(1), tracker.InFlight())
+
+	monitor := &healthfx.Monitor{}
+	monitor.SetReady(true)
+	err = shutdownServer(context.Background(), startServerDeps{
+		Echo:    e,
+		Tracker: tracker,
+		Health:  monitor,
+		Config: serverConfig{
+			ShutdownDrainDelay: 10 * time.Millisecond,
+			ShutdownTimeout:    time.Second,
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, monitor.IsReady())
+	assert.Equal(t, http.StatusNoContent, <-respCode)
+	assert.Equal(t, int64(0), tracker.InFlight())
+}
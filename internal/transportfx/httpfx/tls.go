@@ -0,0 +1,220 @@
+package httpfx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/bosonicalio/enclave/internal/globallog"
+)
+
+var (
+	_tlsVersions = map[string]uint16{
+		"1.0": tls.VersionTLS10,
+		"1.1": tls.VersionTLS11,
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	}
+	_tlsClientAuthTypes = map[string]tls.ClientAuthType{
+		"none":               tls.NoClientCert,
+		"request":            tls.RequestClientCert,
+		"require":            tls.RequireAnyClientCert,
+		"verify_if_given":    tls.VerifyClientCertIfGiven,
+		"require_and_verify": tls.RequireAndVerifyClientCert,
+	}
+)
+
+// tlsMaterial is the set of certificates loaded from the server configuration.
+type tlsMaterial struct {
+	certificate tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+// tlsReloader holds the TLS material used by the HTTP server, reloading it from disk whenever
+// the underlying files change.
+type tlsReloader struct {
+	cfg      serverConfig
+	material atomic.Pointer[tlsMaterial]
+	stop     chan struct{}
+}
+
+// newTLSConfig allocates a [tls.Config] based on `cfg`.
+//
+// Certificates are resolved on every handshake through the returned [tlsReloader], allowing
+// certificate rotation without restarting the server.
+func newTLSConfig(cfg serverConfig) (*tls.Config, *tlsReloader, error) {
+	hasCert := cfg.TLSCertFile != "" || cfg.TLSCert != ""
+	hasKey := cfg.TLSKeyFile != "" || cfg.TLSKey != ""
+	if !hasCert || !hasKey {
+		return nil, nil, errors.New("enclave.http: TLS requires a certificate and a key " +
+			"(HTTP_SERVER_TLS_CERT_FILE/HTTP_SERVER_TLS_CERT and HTTP_SERVER_TLS_KEY_FILE/HTTP_SERVER_TLS_KEY)")
+	}
+
+	cipherSuites, err := parseCipherSuites(cfg.TLSCipherSuites)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hasClientCA := cfg.TLSClientCAFile != "" || cfg.TLSClientCA != ""
+	clientAuth := tls.NoClientCert
+	if cfg.TLSClientAuth != "" {
+		clientAuth = _tlsClientAuthTypes[cfg.TLSClientAuth]
+	} else if hasClientCA {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	if !hasClientCA && (clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert) {
+		return nil, nil, errors.New("enclave.http: TLS client certificate verification requires a client CA " +
+			"(HTTP_SERVER_TLS_CLIENT_CA_FILE or HTTP_SERVER_TLS_CLIENT_CA)")
+	}
+
+	reloader := &tlsReloader{
+		cfg:  cfg,
+		stop: make(chan struct{}),
+	}
+	material, err := reloader.load()
+	if err != nil {
+		return nil, nil, err
+	}
+	reloader.material.Store(material)
+
+	tlsConfig := &tls.Config{
+		MinVersion:   _tlsVersions[cfg.TLSMinVersion],
+		CipherSuites: cipherSuites,
+		ClientAuth:   clientAuth,
+		// the listener is wrapped before serving, so the HTTP server does not advertise HTTP/2 itself
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	tlsConfig.GetConfigForClient = func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+		// cloning the parent configuration keeps its settings (e.g. ALPN protocols and client authentication)
+		current := reloader.material.Load()
+		connConfig := tlsConfig.Clone()
+		connConfig.GetConfigForClient = nil
+		connConfig.Certificates = []tls.Certificate{current.certificate}
+		connConfig.ClientCAs = current.clientCAs
+		return connConfig, nil
+	}
+	return tlsConfig, reloader, nil
+}
+
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	available := make(map[string]uint16, len(tls.CipherSuites()))
+	for _, suite := range tls.CipherSuites() {
+		available[suite.Name] = suite.ID
+	}
+	suites := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("enclave.http: unsupported or insecure TLS cipher suite %q", name)
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
+
+// load reads every certificate from either its file or inline value.
+func (r *tlsReloader) load() (*tlsMaterial, error) {
+	material := &tlsMaterial{
+		modTimes: make(map[string]time.Time, 3),
+	}
+	certPEM, err := r.read(material, r.cfg.TLSCertFile, r.cfg.TLSCert)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := r.read(material, r.cfg.TLSKeyFile, r.cfg.TLSKey)
+	if err != nil {
+		return nil, err
+	}
+	material.certificate, err = tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("enclave.http: invalid TLS certificate/key pair: %w", err)
+	}
+
+	if r.cfg.TLSClientCAFile == "" && r.cfg.TLSClientCA == "" {
+		return material, nil
+	}
+	caPEM, err := r.read(material, r.cfg.TLSClientCAFile, r.cfg.TLSClientCA)
+	if err != nil {
+		return nil, err
+	}
+	material.clientCAs = x509.NewCertPool()
+	if !material.clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("enclave.http: no valid certificates found in TLS client CA bundle")
+	}
+	return material, nil
+}
+
+func (r *tlsReloader) read(material *tlsMaterial, path, inline string) ([]byte, error) {
+	if path == "" {
+		return []byte(inline), nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	material.modTimes[path] = info.ModTime()
+	return os.ReadFile(path)
+}
+
+// hasChanged indicates whether any of the certificate files were modified since they were last loaded.
+func (r *tlsReloader) hasChanged() bool {
+	for path, modTime := range r.material.Load().modTimes {
+		info, err := os.Stat(path)
+		if err != nil {
+			// file might be in the middle of an atomic swap, retry on next tick
+			continue
+		}
+		if !info.ModTime().Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// reload loads certificates from disk if any of them changed. Current certificates are kept if
+// the new ones are not valid.
+func (r *tlsReloader) reload(ctx context.Context) {
+	if !r.hasChanged() {
+		return
+	}
+	material, err := r.load()
+	if err != nil {
+		globallog.Logger().ErrorContext(ctx, "failed to reload tls certificates, keeping current ones",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	r.material.Store(material)
+	globallog.Logger().InfoContext(ctx, "reloaded tls certificates")
+}
+
+// watch polls certificate files every `interval` until [tlsReloader.close] is called.
+func (r *tlsReloader) watch(interval time.Duration) {
+	if interval <= 0 || len(r.material.Load().modTimes) == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.reload(context.Background())
+		}
+	}
+}
+
+func (r *tlsReloader) close() {
+	close(r.stop)
+}
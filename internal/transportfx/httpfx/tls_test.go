@@ -0,0 +1,125 @@
+package httpfx
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCertificate(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t, "enclave-a")
+
+	// Missing key
+	_, _, err := newTLSConfig(serverConfig{TLSCert: string(certPEM), TLSMinVersion: "1.2"})
+	assert.Error(t, err)
+
+	// Insecure cipher suite
+	_, _, err = newTLSConfig(serverConfig{
+		TLSCert:         string(certPEM),
+		TLSKey:          string(keyPEM),
+		TLSMinVersion:   "1.2",
+		TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+	})
+	assert.Error(t, err)
+
+	// Client verification without CA
+	_, _, err = newTLSConfig(serverConfig{
+		TLSCert:       string(certPEM),
+		TLSKey:        string(keyPEM),
+		TLSMinVersion: "1.2",
+		TLSClientAuth: "require_and_verify",
+	})
+	assert.Error(t, err)
+
+	// Inline certificates with mTLS
+	tlsConfig, _, err := newTLSConfig(serverConfig{
+		TLSCert:       string(certPEM),
+		TLSKey:        string(keyPEM),
+		TLSClientCA:   string(certPEM),
+		TLSMinVersion: "1.3",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	connConfig, err := tlsConfig.GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Len(t, connConfig.Certificates, 1)
+	assert.NotNil(t, connConfig.ClientCAs)
+	assert.Equal(t, []string{"h2", "http/1.1"}, connConfig.NextProtos)
+}
+
+func TestTLSReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	certPEM, keyPEM := newTestCertificate(t, "enclave-a")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	tlsConfig, reloader, err := newTLSConfig(serverConfig{
+		TLSCertFile:   certFile,
+		TLSKeyFile:    keyFile,
+		TLSMinVersion: "1.2",
+	})
+	require.NoError(t, err)
+	connConfig, err := tlsConfig.GetConfigForClient(nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(connConfig.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "enclave-a", leaf.Subject.CommonName)
+
+	// Invalid certificate keeps the current one
+	require.NoError(t, os.WriteFile(certFile, []byte("invalid"), 0o600))
+	require.NoError(t, os.Chtimes(certFile, time.Now(), time.Now().Add(time.Minute)))
+	reloader.reload(context.Background())
+	connConfig, err = tlsConfig.GetConfigForClient(nil)
+	require.NoError(t, err)
+	leaf, err = x509.ParseCertificate(connConfig.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "enclave-a", leaf.Subject.CommonName)
+
+	// Rotated certificate
+	certPEM, keyPEM = newTestCertificate(t, "enclave-b")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	require.NoError(t, os.Chtimes(certFile, time.Now(), time.Now().Add(2*time.Minute)))
+	reloader.reload(context.Background())
+	connConfig, err = tlsConfig.GetConfigForClient(nil)
+	require.NoError(t, err)
+	leaf, err = x509.ParseCertificate(connConfig.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "enclave-b", leaf.Subject.CommonName)
+}
@@ -22,6 +22,11 @@ var Module = fx.Module("enclave/validation",
 	),
 )
 
+// Configs are the configurations parsed by [Module].
+var Configs = []osenv.ConfigSpec{
+	osenv.ConfigOf[config](""),
+}
+
 // -- Factory --
 
 func newValidator(cfg config) (validation.Validator, error) {
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerContextKey struct{}
+
+// contextLogger is a logger set in a context, along with the same logger with the context correlation fields
+// bound.
+type contextLogger struct {
+	base  *slog.Logger
+	bound *slog.Logger
+}
+
+// WithLogger sets `logger` in `ctx`.
+//
+// Enclave components (e.g. HTTP middlewares) use this routine to propagate loggers enriched with
+// request-scoped attributes (e.g. request ID). Correlation fields of `ctx` (see [WithFields]) are bound to
+// `logger`.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, contextLogger{
+		base:  logger,
+		bound: bindFields(logger, Fields(ctx)),
+	})
+}
+
+// FromContext retrieves the [slog.Logger] instance from `ctx`. Returns [slog.Default] if no logger was set.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerContextKey{}).(contextLogger)
+	if !ok || logger.bound == nil {
+		return slog.Default()
+	}
+	return logger.bound
+}
+
+func bindFields(logger *slog.Logger, fields []slog.Attr) *slog.Logger {
+	if logger == nil || len(fields) == 0 {
+		return logger
+	}
+	args := make([]any, 0, len(fields))
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	return logger.With(args...)
+}
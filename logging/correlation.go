@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// Keys of the correlation fields appended to log records by [ContextHandler].
+const (
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+	RequestIDKey = "request_id"
+	TenantIDKey  = "tenant_id"
+	UserIDKey    = "user_id"
+	// PrincipalIDKey is the identifier of the principal authenticated by the enclave authentication module.
+	PrincipalIDKey = "principal_id"
+)
+
+type fieldsContextKey struct{}
+
+// WithFields adds correlation `fields` to `ctx`. They are appended to every record logged with this context
+// (e.g. [slog.Logger.InfoContext]) by a [ContextHandler], and bound to the logger of `ctx` (see [FromContext]).
+// Fields with the same key replace previous ones.
+func WithFields(ctx context.Context, fields ...slog.Attr) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	current := Fields(ctx)
+	merged := make([]slog.Attr, 0, len(current)+len(fields))
+	for _, field := range current {
+		if !slices.ContainsFunc(fields, func(a slog.Attr) bool { return a.Key == field.Key }) {
+			merged = append(merged, field)
+		}
+	}
+	merged = append(merged, fields...)
+	ctx = context.WithValue(ctx, fieldsContextKey{}, merged)
+	if logger, ok := ctx.Value(loggerContextKey{}).(contextLogger); ok {
+		// bound to the base logger, so replaced fields are not duplicated
+		ctx = context.WithValue(ctx, loggerContextKey{}, contextLogger{
+			base:  logger.base,
+			bound: bindFields(logger.base, merged),
+		})
+	}
+	return ctx
+}
+
+// Fields retrieves the correlation fields set in `ctx` with [WithFields].
+func Fields(ctx context.Context) []slog.Attr {
+	fields, _ := ctx.Value(fieldsContextKey{}).([]slog.Attr)
+	return fields
+}
+
+// WithRequestID sets the request identifier correlation field in `ctx`.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return WithFields(ctx, slog.String(RequestIDKey, id))
+}
+
+// WithTenantID sets the tenant identifier correlation field in `ctx`.
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return WithFields(ctx, slog.String(TenantIDKey, id))
+}
+
+// WithUserID sets the user identifier correlation field in `ctx`.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return WithFields(ctx, slog.String(UserIDKey, id))
+}
+
+// WithPrincipalID sets the authenticated principal identifier correlation field in `ctx`.
+func WithPrincipalID(ctx context.Context, id string) context.Context {
+	return WithFields(ctx, slog.String(PrincipalIDKey, id))
+}
+
+// Field retrieves the value of the correlation field `key` from `ctx`. Returns an empty string if not set.
+func Field(ctx context.Context, key string) string {
+	for _, field := range Fields(ctx) {
+		if field.Key == key {
+			return field.Value.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextHandler is a [slog.Handler] appending correlation fields from the record context to every record:
+// trace and span IDs of the current OpenTelemetry span, and fields set with [WithFields] (e.g. request, tenant
+// and user IDs).
+//
+// Only records logged with a context (e.g. [slog.Logger.InfoContext]) are correlated. Fields already bound to the
+// logger (e.g. loggers retrieved with [FromContext]) are not appended twice. Fields are always appended at the top
+// level, even if the logger has groups (see [slog.Logger.WithGroup]).
+type ContextHandler struct {
+	next slog.Handler
+	// root is the wrapped handler before the first group, scopes are the groups and attributes added since.
+	root   slog.Handler
+	scopes []handlerScope
+	// bound are the keys of the top-level attributes bound to the handler.
+	bound []string
+}
+
+// handlerScope is either a group or attributes added to a handler.
+type handlerScope struct {
+	group string
+	attrs []slog.Attr
+}
+
+// compile-time assertion
+var _ slog.Handler = (*ContextHandler)(nil)
+
+// NewContextHandler allocates a new [ContextHandler] wrapping `next`.
+func NewContextHandler(next slog.Handler) ContextHandler {
+	return ContextHandler{next: next, root: next}
+}
+
+func (h ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx == nil {
+		return h.next.Handle(ctx, record)
+	}
+	fields := h.unbound(Fields(ctx))
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if spanCtx.IsValid() && !slices.Contains(h.bound, TraceIDKey) {
+		fields = append([]slog.Attr{
+			slog.String(TraceIDKey, spanCtx.TraceID().String()),
+			slog.String(SpanIDKey, spanCtx.SpanID().String()),
+		}, fields...)
+	}
+	if len(fields) == 0 {
+		return h.next.Handle(ctx, record)
+	}
+
+	if len(h.scopes) == 0 {
+		record = record.Clone()
+		record.AddAttrs(fields...)
+		return h.next.Handle(ctx, record)
+	}
+	// record attributes belong to the innermost group, so fields are bound before the groups instead
+	next := h.root.WithAttrs(fields)
+	for _, scope := range h.scopes {
+		if scope.group != "" {
+			next = next.WithGroup(scope.group)
+			continue
+		}
+		next = next.WithAttrs(scope.attrs)
+	}
+	return next.Handle(ctx, record)
+}
+
+// unbound returns the `fields` not bound to the handler.
+func (h ContextHandler) unbound(fields []slog.Attr) []slog.Attr {
+	if len(h.bound) == 0 {
+		return fields
+	}
+	unbound := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		if !slices.Contains(h.bound, field.Key) {
+			unbound = append(unbound, field)
+		}
+	}
+	return unbound
+}
+
+func (h ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.scopes) > 0 {
+		return ContextHandler{
+			next:   h.next.WithAttrs(attrs),
+			root:   h.root,
+			scopes: append(slices.Clip(h.scopes), handlerScope{attrs: attrs}),
+			bound:  h.bound,
+		}
+	}
+	bound := slices.Clone(h.bound)
+	for _, attr := range attrs {
+		bound = append(bound, attr.Key)
+	}
+	next := h.next.WithAttrs(attrs)
+	return ContextHandler{next: next, root: next, bound: bound}
+}
+
+func (h ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return ContextHandler{
+		next:   h.next.WithGroup(name),
+		root:   h.root,
+		scopes: append(slices.Clip(h.scopes), handlerScope{group: name}),
+		bound:  h.bound,
+	}
+}
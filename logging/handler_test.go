@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithTenantID(ctx, "acme")
+	ctx = WithUserID(ctx, "user-1")
+	ctx = WithUserID(ctx, "user-2")
+	ctx = WithFields(ctx, slog.String("order_id", "42"))
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	}))
+	assert.Equal(t, "req-1", Field(ctx, RequestIDKey))
+
+	logger.With(slog.String("component", "orders")).InfoContext(ctx, "created order")
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record[RequestIDKey])
+	assert.Equal(t, "acme", record[TenantIDKey])
+	assert.Equal(t, "user-2", record[UserIDKey])
+	assert.Equal(t, "42", record["order_id"])
+	assert.Equal(t, trace.TraceID{1}.String(), record[TraceIDKey])
+	assert.Equal(t, trace.SpanID{2}.String(), record[SpanIDKey])
+	assert.Equal(t, "orders", record["component"])
+
+	// Records without context are not correlated
+	buf.Reset()
+	logger.Info("started")
+	assert.NotContains(t, buf.String(), RequestIDKey)
+}
+
+func TestContextHandler_ContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := WithLogger(context.Background(), logger)
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithPrincipalID(ctx, "user-1")
+	ctx = WithPrincipalID(ctx, "user-2")
+
+	// fields are bound to the context logger, even without context
+	FromContext(ctx).Info("handling request")
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record[RequestIDKey])
+	assert.Equal(t, "user-2", record[PrincipalIDKey])
+	assert.Equal(t, 1, strings.Count(buf.String(), PrincipalIDKey))
+
+	// and not appended twice to records logged with context
+	buf.Reset()
+	FromContext(ctx).InfoContext(ctx, "handling request")
+	assert.Equal(t, 1, strings.Count(buf.String(), RequestIDKey))
+	assert.Equal(t, 1, strings.Count(buf.String(), PrincipalIDKey))
+}
+
+func TestContextHandler_WithGroup(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.With(slog.String("component", "orders")).
+		WithGroup("order").
+		With(slog.String("id", "42")).
+		InfoContext(ctx, "created order", slog.Int("items", 3))
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record[RequestIDKey])
+	assert.Equal(t, "orders", record["component"])
+	assert.Equal(t, map[string]any{"id": "42", "items": float64(3)}, record["order"])
+}
@@ -2,11 +2,14 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"log/slog"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/bosonicalio/enclave/internal/persistencefx/sqlfx"
 )
@@ -44,3 +47,54 @@ func logDBInfo(logger *slog.Logger, config sqlfx.Config) {
 		),
 	)
 }
+
+// registerPoolMetrics records the connection pool statistics of `db` if `mp` is not nil (i.e. the metrics module
+// is present).
+func registerPoolMetrics(db *sql.DB, mp metric.MeterProvider) error {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter("github.com/bosonicalio/enclave/postgres")
+	connections, err := meter.Int64ObservableUpDownCounter("db.client.connection.count",
+		metric.WithDescription("Number of connections of the pool, by state (idle or used)."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	maxConnections, err := meter.Int64ObservableUpDownCounter("db.client.connection.max",
+		metric.WithDescription("Maximum number of open connections allowed."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	waitCount, err := meter.Int64ObservableCounter("db.client.connection.wait_count",
+		metric.WithDescription("Total number of connections waited for."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	waitDuration, err := meter.Float64ObservableCounter("db.client.connection.wait_duration",
+		metric.WithDescription("Total time blocked waiting for a new connection."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	poolAttr := attribute.String("db.client.connection.pool.name", "postgres")
+	_, err = meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		stats := db.Stats()
+		observer.ObserveInt64(connections, int64(stats.Idle),
+			metric.WithAttributes(poolAttr, attribute.String("db.client.connection.state", "idle")))
+		observer.ObserveInt64(connections, int64(stats.InUse),
+			metric.WithAttributes(poolAttr, attribute.String("db.client.connection.state", "used")))
+		observer.ObserveInt64(maxConnections, int64(stats.MaxOpenConnections), metric.WithAttributes(poolAttr))
+		observer.ObserveInt64(waitCount, stats.WaitCount, metric.WithAttributes(poolAttr))
+		observer.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), metric.WithAttributes(poolAttr))
+		return nil
+	}, connections, maxConnections, waitCount, waitDuration)
+	return err
+}
@@ -16,6 +16,10 @@ var module = fx.Options(
 	),
 	fx.Invoke(
 		logDBInfo,
+		fx.Annotate(
+			registerPoolMetrics,
+			fx.ParamTags("", `optional:"true"`), // meter provider is optional
+		),
 	),
 )
 
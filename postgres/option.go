@@ -2,11 +2,15 @@ package postgres
 
 import (
 	"github.com/bosonicalio/enclave"
+	"github.com/bosonicalio/enclave/internal/persistencefx/sqlfx"
 )
 
 // WithPostgres returns an enclave option that includes the Postgres module.
+//
+// The connection pool is configured with the SQL module configuration (e.g. `SQL_CONNECTION_STRING`), checked
+// along with every other module configuration.
 func WithPostgres() enclave.Option {
-	return enclave.WithFxOptions(
-		module,
+	return enclave.WithModule(module,
+		sqlfx.Configs...,
 	)
 }